@@ -0,0 +1,161 @@
+// Package analysis reasons about the rules symbolically: it decides whether a
+// rule can ever be true, whether a rule implies another one and whether two
+// rules are equivalent, and finds the data proving it. It also finds example
+// data driving a rule to each of its outcomes.
+//
+// The analysis covers the rules combining "and", "or", "!", "!!" and "if"
+// over comparisons ("==", "!=", "===", "!==", "<", "<=", ">" and ">="),
+// "in", "missing", "missing_some" and variables, whose operands are literals
+// or variables with a literal path. Parts of a rule that don't read the data
+// are evaluated once, whatever their operators. The other operators make the
+// functions return an *UnsupportedError.
+//
+// Rules are compared by the truthiness of their results, and a rule failing
+// on some data counts as false for it. Since every comparison involves
+// literals, the values of each variable fall in a finite number of ranges
+// that behave the same, so the analysis tries one value of each range, in
+// the types allowed by the Domains of the variables.
+package analysis
+
+import (
+	"errors"
+	"fmt"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// ErrTooComplex is returned when deciding a question takes too many steps.
+var ErrTooComplex = errors.New("analysis: the rules are too complex to be analyzed")
+
+// UnsupportedError represents a part of a rule outside the subset analyzed.
+// Path locates it in the rule.
+type UnsupportedError struct {
+	Path   jsonlogic.Path
+	Reason string
+}
+
+func (e *UnsupportedError) Error() string {
+	location := e.Path.Pointer()
+	if location == "" {
+		location = "(root)"
+	}
+
+	return fmt.Sprintf("analysis: %s: %s", location, e.Reason)
+}
+
+// Type is a set of JSON types a variable may hold, combined with |.
+type Type uint8
+
+const (
+	// Null allows the variable to be null or missing.
+	Null Type = 1 << iota
+	Boolean
+	Number
+	String
+
+	// Any allows every type but arrays and objects.
+	Any = Null | Boolean | Number | String
+)
+
+// Domains holds the types of the variables, by path as written in the rules.
+// The variables without a domain may hold values of Any type.
+type Domains map[string]Type
+
+func (d Domains) of(path string) Type {
+	if t, ok := d[path]; ok && t != 0 {
+		return t
+	}
+	return Any
+}
+
+// Satisfiable reports whether some data makes the rule true, and returns
+// such data.
+//
+// Parameters:
+//   - rule: interface{} representing the rule to be analyzed
+//   - domains: the types of the variables, nil to allow any type
+//
+// Returns:
+//   - ok: true when the rule can be true
+//   - data: data making the rule true, nil when there is none
+//   - err: *UnsupportedError for rules outside the subset analyzed, or ErrTooComplex
+func Satisfiable(rule any, domains Domains) (bool, map[string]any, error) {
+	a := newAnalyzer(domains)
+
+	root, err := a.build(rule, jsonlogic.Path{})
+	if err != nil {
+		return false, nil, err
+	}
+
+	return a.solve(root)
+}
+
+// Implies reports whether every data making the rule a true also makes the
+// rule b true, and returns a counterexample otherwise.
+//
+// Parameters:
+//   - a: interface{} representing the rule assumed to be true
+//   - b: interface{} representing the rule implied by a
+//   - domains: the types of the variables, nil to allow any type
+//
+// Returns:
+//   - ok: true when a implies b
+//   - counterexample: data making a true and b false, nil when a implies b
+//   - err: *UnsupportedError for rules outside the subset analyzed, or ErrTooComplex
+func Implies(a, b any, domains Domains) (bool, map[string]any, error) {
+	an := newAnalyzer(domains)
+
+	left, right, err := an.buildPair(a, b)
+	if err != nil {
+		return false, nil, err
+	}
+
+	found, counterexample, err := an.solve(and(left, not(right)))
+	if err != nil {
+		return false, nil, err
+	}
+
+	return !found, counterexample, nil
+}
+
+// Equivalent reports whether the rules are true for the same data, and
+// returns a counterexample otherwise.
+//
+// Parameters:
+//   - a: interface{} representing a rule
+//   - b: interface{} representing the rule compared to a
+//   - domains: the types of the variables, nil to allow any type
+//
+// Returns:
+//   - ok: true when the rules are equivalent
+//   - counterexample: data making one rule true and the other false, nil when they are equivalent
+//   - err: *UnsupportedError for rules outside the subset analyzed, or ErrTooComplex
+func Equivalent(a, b any, domains Domains) (bool, map[string]any, error) {
+	an := newAnalyzer(domains)
+
+	left, right, err := an.buildPair(a, b)
+	if err != nil {
+		return false, nil, err
+	}
+
+	found, counterexample, err := an.solve(or(and(left, not(right)), and(not(left), right)))
+	if err != nil {
+		return false, nil, err
+	}
+
+	return !found, counterexample, nil
+}
+
+func (a *analyzer) buildPair(left, right any) (*node, *node, error) {
+	l, err := a.build(left, jsonlogic.Path{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	r, err := a.build(right, jsonlogic.Path{})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return l, r, nil
+}
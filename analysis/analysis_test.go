@@ -0,0 +1,210 @@
+package analysis_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/analysis"
+)
+
+func parse(t *testing.T, rule string) any {
+	t.Helper()
+
+	var parsed any
+	if err := json.Unmarshal([]byte(rule), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func truthy(t *testing.T, rule string, data map[string]any) bool {
+	t.Helper()
+
+	result, err := jsonlogic.ApplyInterface(map[string]any{"!!": []any{parse(t, rule)}}, data)
+	if err != nil {
+		return false
+	}
+	return result == true
+}
+
+func TestSatisfiable(t *testing.T) {
+	scenarios := map[string]struct {
+		rule     string
+		domains  analysis.Domains
+		expected bool
+	}{
+		"contradicting bounds": {
+			rule:     `{"and": [{">": [{"var": "x"}, 5]}, {"<": [{"var": "x"}, 3]}]}`,
+			domains:  analysis.Domains{"x": analysis.Number},
+			expected: false,
+		},
+		"compatible bounds": {
+			rule:     `{"and": [{">": [{"var": "x"}, 3]}, {"<": [{"var": "x"}, 5]}]}`,
+			domains:  analysis.Domains{"x": analysis.Number},
+			expected: true,
+		},
+		"between": {
+			rule:     `{"<": [1, {"var": "x"}, 2]}`,
+			domains:  analysis.Domains{"x": analysis.Number},
+			expected: true,
+		},
+		"equal to two values": {
+			rule:     `{"and": [{"==": [{"var": "country"}, "BR"]}, {"==": [{"var": "country"}, "PT"]}]}`,
+			expected: false,
+		},
+		"in and not in the same list": {
+			rule:     `{"and": [{"in": [{"var": "plan"}, ["pro", "team"]]}, {"!": {"in": [{"var": "plan"}, ["team", "pro"]]}}]}`,
+			expected: false,
+		},
+		"missing and present": {
+			rule:     `{"and": [{"missing": ["email"]}, {"==": [{"var": "email"}, "a@b.c"]}]}`,
+			expected: false,
+		},
+		"required variable never missing": {
+			rule:     `{"missing": ["email"]}`,
+			domains:  analysis.Domains{"email": analysis.String},
+			expected: false,
+		},
+		"substring": {
+			rule:     `{"and": [{"in": ["@", {"var": "email"}]}, {"in": [".", {"var": "email"}]}]}`,
+			domains:  analysis.Domains{"email": analysis.String},
+			expected: true,
+		},
+		"variables compared to each other": {
+			rule:     `{"and": [{"<": [{"var": "a"}, {"var": "b"}]}, {"<": [{"var": "b"}, {"var": "c"}]}, {"<": [{"var": "c"}, {"var": "a"}]}]}`,
+			domains:  analysis.Domains{"a": analysis.Number, "b": analysis.Number, "c": analysis.Number},
+			expected: false,
+		},
+		"strictly ordered variables": {
+			rule:     `{"and": [{"<": [{"var": "a"}, {"var": "b"}]}, {"<": [{"var": "b"}, {"var": "c"}]}, {"<": [0, {"var": "a"}]}, {"<": [{"var": "c"}, 1]}]}`,
+			domains:  analysis.Domains{"a": analysis.Number, "b": analysis.Number, "c": analysis.Number},
+			expected: true,
+		},
+		"loose equality between a string and a number": {
+			rule:     `{"and": [{"==": [{"var": "x"}, 1]}, {"!==": [{"var": "x"}, 1]}]}`,
+			expected: true,
+		},
+		"if branches": {
+			rule:     `{"if": [{">": [{"var": "age"}, 18]}, {"<": [{"var": "age"}, 10]}, false]}`,
+			domains:  analysis.Domains{"age": analysis.Number},
+			expected: false,
+		},
+		"constant parts": {
+			rule:     `{"and": [{"==": [{"+": [1, 1]}, 2]}, {"var": "active"}]}`,
+			expected: true,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			ok, data, err := analysis.Satisfiable(parse(t, scenario.rule), scenario.domains)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, ok)
+
+			if ok {
+				assert.True(t, truthy(t, scenario.rule, data), "%v", data)
+			} else {
+				assert.Nil(t, data)
+			}
+		})
+	}
+}
+
+func TestSatisfiableWitness(t *testing.T) {
+	ok, data, err := analysis.Satisfiable(parse(t, `{"and": [{">": [{"var": "user.age"}, 5]}, {"<": [{"var": "user.age"}, 8]}]}`), analysis.Domains{"user.age": analysis.Number})
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, map[string]any{"user": map[string]any{"age": float64(6)}}, data)
+}
+
+func TestImplies(t *testing.T) {
+	domains := analysis.Domains{"x": analysis.Number}
+
+	ok, counterexample, err := analysis.Implies(parse(t, `{">": [{"var": "x"}, 10]}`), parse(t, `{">=": [{"var": "x"}, 5]}`), domains)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Nil(t, counterexample)
+
+	ok, counterexample, err = analysis.Implies(parse(t, `{">=": [{"var": "x"}, 5]}`), parse(t, `{">": [{"var": "x"}, 10]}`), domains)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	assert.True(t, truthy(t, `{"and": [{">=": [{"var": "x"}, 5]}, {"<=": [{"var": "x"}, 10]}]}`, counterexample), "%v", counterexample)
+}
+
+func TestEquivalent(t *testing.T) {
+	scenarios := map[string]struct {
+		a        string
+		b        string
+		domains  analysis.Domains
+		expected bool
+	}{
+		"de morgan": {
+			a:        `{"!": {"and": [{"var": "a"}, {"var": "b"}]}}`,
+			b:        `{"or": [{"!": {"var": "a"}}, {"!": {"var": "b"}}]}`,
+			expected: true,
+		},
+		"reordered operands": {
+			a:        `{"and": [{"==": [{"var": "country"}, "BR"]}, {">=": [{"var": "age"}, 18]}]}`,
+			b:        `{"and": [{"<=": [18, {"var": "age"}]}, {"==": ["BR", {"var": "country"}]}]}`,
+			expected: true,
+		},
+		"in instead of or": {
+			a:        `{"or": [{"===": [{"var": "plan"}, "pro"]}, {"===": [{"var": "plan"}, "team"]}]}`,
+			b:        `{"in": [{"var": "plan"}, ["pro", "team"]]}`,
+			domains:  analysis.Domains{"plan": analysis.String | analysis.Null},
+			expected: true,
+		},
+		"if as boolean logic": {
+			a:        `{"if": [{"var": "vip"}, true, {">": [{"var": "total"}, 100]}]}`,
+			b:        `{"or": [{"var": "vip"}, {">": [{"var": "total"}, 100]}]}`,
+			expected: true,
+		},
+		"off by one": {
+			a:        `{">": [{"var": "age"}, 18]}`,
+			b:        `{">=": [{"var": "age"}, 18]}`,
+			domains:  analysis.Domains{"age": analysis.Number},
+			expected: false,
+		},
+		"loose and strict equality": {
+			a:        `{"==": [{"var": "x"}, 1]}`,
+			b:        `{"===": [{"var": "x"}, 1]}`,
+			expected: false,
+		},
+		"same with numbers only": {
+			a:        `{"==": [{"var": "x"}, 1]}`,
+			b:        `{"===": [{"var": "x"}, 1]}`,
+			domains:  analysis.Domains{"x": analysis.Number},
+			expected: true,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			ok, counterexample, err := analysis.Equivalent(parse(t, scenario.a), parse(t, scenario.b), scenario.domains)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, ok)
+
+			if !ok {
+				assert.NotEqual(t, truthy(t, scenario.a, counterexample), truthy(t, scenario.b, counterexample), "%v", counterexample)
+			}
+		})
+	}
+}
+
+func TestUnsupportedRules(t *testing.T) {
+	_, _, err := analysis.Satisfiable(parse(t, `{"and": [true, {">": [{"+": [{"var": "x"}, 1]}, 5]}]}`), nil)
+
+	var unsupported *analysis.UnsupportedError
+	assert.ErrorAs(t, err, &unsupported)
+	assert.Equal(t, jsonlogic.Path{"and", "1", ">", "0"}, unsupported.Path)
+	assert.EqualError(t, err, "analysis: /and/1/>/0: the operands must be literals or variables with a literal path")
+
+	_, _, err = analysis.Satisfiable(parse(t, `{"some": [{"var": "items"}, {">": [{"var": ""}, 1]}]}`), nil)
+	assert.EqualError(t, err, `analysis: (root): the operator "some" is not supported`)
+
+	_, _, err = analysis.Equivalent(parse(t, `{"var": "user"}`), parse(t, `{"var": "user.name"}`), nil)
+	assert.EqualError(t, err, `analysis: (root): the variables "user" and "user.name" overlap`)
+}
@@ -0,0 +1,396 @@
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// absent is the value of the variables missing from the data.
+type absentValue struct{}
+
+var absent any = absentValue{}
+
+// maxSteps bounds the assignments tried to answer a question.
+const maxSteps = 1 << 20
+
+// variable is a variable read by the rules, with the literals it's compared to.
+type variable struct {
+	path       jsonlogic.Path
+	typ        Type
+	numbers    []float64
+	strings    []string
+	substrings []string
+	ordered    bool
+}
+
+type analyzer struct {
+	domains   Domains
+	variables map[string]*variable
+	order     []string
+
+	// parent links the variables compared to each other, which share
+	// their literals.
+	parent map[string]string
+}
+
+func newAnalyzer(domains Domains) *analyzer {
+	return &analyzer{
+		domains:   domains,
+		variables: make(map[string]*variable),
+		parent:    make(map[string]string),
+	}
+}
+
+// declare records a variable, rejecting the paths reading another variable
+// as an object.
+func (a *analyzer) declare(name string, p jsonlogic.Path, path jsonlogic.Path) error {
+	if _, ok := a.variables[name]; ok {
+		return nil
+	}
+
+	for _, other := range a.order {
+		if isPrefix(a.variables[other].path, p) || isPrefix(p, a.variables[other].path) {
+			return &UnsupportedError{Path: path, Reason: fmt.Sprintf("the variables \"%s\" and \"%s\" overlap", other, name)}
+		}
+	}
+
+	a.variables[name] = &variable{path: p, typ: a.domains.of(name)}
+	a.order = append(a.order, name)
+	a.parent[name] = name
+
+	return nil
+}
+
+func isPrefix(prefix, p jsonlogic.Path) bool {
+	if len(prefix) > len(p) {
+		return false
+	}
+	for i := range prefix {
+		if prefix[i] != p[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// constrain records the literals the variable is compared to, and links it
+// to the other variables of the comparison.
+func (a *analyzer) constrain(name string, vars []string, literals []any) {
+	v := a.variables[name]
+
+	for _, literal := range literals {
+		values, ok := literal.([]any)
+		if !ok {
+			values = []any{literal}
+		}
+
+		for _, value := range values {
+			switch c := value.(type) {
+			case float64:
+				v.numbers = append(v.numbers, c)
+				v.strings = append(v.strings, strconv.FormatFloat(c, 'f', -1, 64))
+			case string:
+				v.strings = append(v.strings, c)
+				if n, err := strconv.ParseFloat(strings.TrimSpace(c), 64); err == nil {
+					v.numbers = append(v.numbers, n)
+				}
+			case bool:
+				v.numbers = append(v.numbers, 1)
+			}
+		}
+	}
+
+	for _, other := range vars {
+		a.union(name, other)
+	}
+}
+
+func (a *analyzer) find(name string) string {
+	for a.parent[name] != name {
+		name = a.parent[name]
+	}
+	return name
+}
+
+func (a *analyzer) union(x, y string) {
+	if rx, ry := a.find(x), a.find(y); rx != ry {
+		a.parent[ry] = rx
+	}
+}
+
+// values returns, for every variable, one value of each range of values
+// behaving the same in the comparisons, in the types of its domain.
+func (a *analyzer) values() map[string][]any {
+	type group struct {
+		size       int
+		numbers    []float64
+		strings    []string
+		substrings []string
+		ordered    bool
+	}
+
+	groups := make(map[string]*group)
+	for _, name := range a.order {
+		root := a.find(name)
+		g, ok := groups[root]
+		if !ok {
+			g = &group{}
+			groups[root] = g
+		}
+
+		v := a.variables[name]
+		g.size++
+		g.numbers = append(g.numbers, v.numbers...)
+		g.strings = append(g.strings, v.strings...)
+		g.substrings = append(g.substrings, v.substrings...)
+		g.ordered = g.ordered || v.ordered
+	}
+
+	values := make(map[string][]any, len(a.order))
+	for _, name := range a.order {
+		g := groups[a.find(name)]
+		typ := a.variables[name].typ
+
+		var domain []any
+		if typ&Null != 0 {
+			domain = append(domain, absent, nil)
+		}
+		if typ&Boolean != 0 {
+			domain = append(domain, false, true)
+		}
+
+		numbers := numberValues(g.numbers, g.size)
+		if typ&Number != 0 {
+			for _, n := range numbers {
+				domain = append(domain, n)
+			}
+		}
+		if typ&String != 0 {
+			extra := []string{}
+			if g.ordered {
+				// strings holding numbers compare as numbers
+				for _, n := range numbers {
+					extra = append(extra, strconv.FormatFloat(n, 'f', -1, 64))
+				}
+			}
+			for _, s := range stringValues(append(g.strings, extra...), g.substrings, g.size, g.ordered) {
+				domain = append(domain, s)
+			}
+		}
+
+		values[name] = domain
+	}
+
+	return values
+}
+
+// numberValues returns the literals, and k numbers in each range between
+// them, below them and above them, in ascending order.
+func numberValues(literals []float64, k int) []float64 {
+	set := map[float64]bool{0: true}
+	for _, n := range literals {
+		if !math.IsNaN(n) && !math.IsInf(n, 0) {
+			set[n] = true
+		}
+	}
+
+	sorted := make([]float64, 0, len(set))
+	for n := range set {
+		sorted = append(sorted, n)
+	}
+	sort.Float64s(sorted)
+
+	values := append([]float64(nil), sorted...)
+	for j := 1; j <= k; j++ {
+		values = append(values, sorted[0]-float64(j), sorted[len(sorted)-1]+float64(j))
+	}
+
+	for i := 0; i+1 < len(sorted); i++ {
+		lo, hi := sorted[i], sorted[i+1]
+
+		first, last := math.Floor(lo)+1, math.Ceil(hi)-1
+		integers := last - first + 1
+
+		for j := 1; j <= k; j++ {
+			if integers >= float64(k) {
+				values = append(values, first+float64(j-1))
+			} else {
+				values = append(values, lo+(hi-lo)*float64(j)/float64(k+1))
+			}
+		}
+	}
+
+	sort.Float64s(values)
+
+	return values
+}
+
+// stringValues returns the literals, the empty string, k strings different
+// from every literal and, when the strings are ordered, k strings in each
+// range between them. Every concatenation of the substrings looked for is
+// included too.
+func stringValues(literals, substrings []string, k int, ordered bool) []string {
+	set := map[string]bool{"": true}
+	for _, s := range literals {
+		set[s] = true
+	}
+
+	for _, combination := range concatenations(substrings) {
+		set[combination] = true
+	}
+
+	constants := make([]string, 0, len(set))
+	for s := range set {
+		constants = append(constants, s)
+	}
+	sort.Strings(constants)
+
+	for i, fresh := 0, 0; i < k; fresh++ {
+		s := string(rune('a' + fresh%26))
+		if fresh >= 26 {
+			s += strconv.Itoa(fresh / 26)
+		}
+		if !set[s] {
+			set[s] = true
+			i++
+		}
+	}
+
+	if ordered {
+		for i, lo := range constants {
+			hi, bounded := "", i+1 < len(constants)
+			if bounded {
+				hi = constants[i+1]
+			}
+
+			found := 0
+			for _, suffix := range []string{"a", "b", "c", "0", " ", "\x00", "\x00\x00", "\x00\x00\x00"} {
+				s := lo + suffix
+				if found < k && !set[s] && s > lo && (!bounded || s < hi) {
+					set[s] = true
+					found++
+				}
+			}
+		}
+	}
+
+	values := make([]string, 0, len(set))
+	for s := range set {
+		values = append(values, s)
+	}
+	sort.Strings(values)
+
+	return values
+}
+
+// concatenations returns the strings made of non-empty combinations of the
+// substrings, up to four of them.
+func concatenations(substrings []string) []string {
+	unique := []string{}
+	for _, s := range substrings {
+		unique = appendUnique(unique, s)
+	}
+	if len(unique) > 4 {
+		return append(unique, strings.Join(unique, ""))
+	}
+
+	var result []string
+	for mask := 1; mask < 1<<len(unique); mask++ {
+		var s strings.Builder
+		for i, substring := range unique {
+			if mask&(1<<i) != 0 {
+				s.WriteString(substring)
+			}
+		}
+		result = append(result, s.String())
+	}
+
+	return result
+}
+
+// solve looks for an assignment of the variables making the formula true, and
+// returns the data holding it.
+func (a *analyzer) solve(root *node) (bool, map[string]any, error) {
+	values := a.values()
+	assignment := make(map[string]any, len(a.order))
+	steps := 0
+
+	var search func(i int) (bool, error)
+	search = func(i int) (bool, error) {
+		steps++
+		if steps > maxSteps {
+			return false, ErrTooComplex
+		}
+
+		switch root.eval(assignment) {
+		case isTrue:
+			return true, nil
+		case isFalse:
+			return false, nil
+		}
+
+		name := a.order[i]
+		for _, value := range values[name] {
+			assignment[name] = value
+			found, err := search(i + 1)
+			if found || err != nil {
+				return found, err
+			}
+		}
+		delete(assignment, name)
+
+		return false, nil
+	}
+
+	found, err := search(0)
+	if err != nil || !found {
+		return false, nil, err
+	}
+
+	complete := make(map[string]any, len(a.order))
+	for _, name := range a.order {
+		value, ok := assignment[name]
+		if !ok && len(values[name]) > 0 {
+			value = values[name][0]
+		}
+		complete[name] = value
+	}
+
+	a.minimize(root, complete)
+
+	return true, a.document(complete), nil
+}
+
+// minimize removes from the assignment the variables the formula holds
+// without, when their domain allows them to be missing.
+func (a *analyzer) minimize(root *node, assignment map[string]any) {
+	for _, name := range a.order {
+		value := assignment[name]
+		if value == absent || a.variables[name].typ&Null == 0 {
+			continue
+		}
+
+		assignment[name] = absent
+		if root.eval(assignment) != isTrue {
+			assignment[name] = value
+		}
+	}
+}
+
+// document returns the data holding the assignment.
+func (a *analyzer) document(assignment map[string]any) map[string]any {
+	data := make(map[string]any)
+
+	for _, name := range a.order {
+		if value, ok := assignment[name]; ok && value != absent {
+			set(data, a.variables[name].path, value)
+		}
+	}
+
+	return data
+}
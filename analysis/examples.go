@@ -0,0 +1,181 @@
+package analysis
+
+import (
+	"strconv"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// Example is data driving a rule to an outcome: making it true, making it
+// false, or taking a branch of one of its "if" operations.
+type Example struct {
+	// Name is "true", "false" or "branch" followed by the location of the
+	// branch as a JSON Pointer.
+	Name string
+
+	// Path locates the branch taken, and is nil for the other examples.
+	Path jsonlogic.Path
+
+	// Data holds only the variables needed to reach the outcome.
+	Data map[string]any
+
+	// Result is the result of the rule applied to the data.
+	Result any
+}
+
+// Examples returns minimal data making the rule true, making it false and
+// taking every branch of its "if" operations, skipping the outcomes no data
+// reaches. The branches of an "if" nested in another one are reached through
+// the conditions of the outer one, but "and" and "or" are assumed to evaluate
+// all their operands.
+//
+// The examples making the rule true or false need the whole rule to be in
+// the subset analyzed, and the branches need the conditions before them to
+// be. The examples found are returned along with the first *UnsupportedError
+// met.
+//
+// Parameters:
+//   - rule: interface{} representing the rule to find examples for
+//   - domains: the types of the variables, nil to allow any type
+//
+// Returns:
+//   - examples: the data reaching each outcome, in the order of the outcomes
+//   - err: *UnsupportedError for the parts of the rule outside the subset analyzed, or ErrTooComplex
+func Examples(rule any, domains Domains) ([]Example, error) {
+	var examples []Example
+	var unsupported error
+
+	add := func(name string, path jsonlogic.Path, build func(a *analyzer) (*node, error)) error {
+		a := newAnalyzer(domains)
+
+		root, err := build(a)
+		if err != nil {
+			if unsupported == nil {
+				unsupported = err
+			}
+			return nil
+		}
+
+		found, data, err := a.solve(root)
+		if err != nil || !found {
+			return err
+		}
+
+		result, err := jsonlogic.ApplyInterface(rule, data)
+		if err != nil {
+			return nil
+		}
+
+		examples = append(examples, Example{Name: name, Path: path, Data: data, Result: result})
+		return nil
+	}
+
+	for _, outcome := range []bool{true, false} {
+		outcome := outcome
+		err := add(strconv.FormatBool(outcome), nil, func(a *analyzer) (*node, error) {
+			root, err := a.build(rule, jsonlogic.Path{})
+			if err != nil || outcome {
+				return root, err
+			}
+			return not(root), nil
+		})
+		if err != nil {
+			return examples, err
+		}
+	}
+
+	for _, b := range branches(rule, jsonlogic.Path{}, nil) {
+		b := b
+		err := add("branch "+b.path.Pointer(), b.path, func(a *analyzer) (*node, error) {
+			conditions := make([]*node, len(b.conditions))
+			for i, condition := range b.conditions {
+				n, err := a.build(condition.rule, condition.path)
+				if err != nil {
+					return nil, err
+				}
+				if !condition.holds {
+					n = not(n)
+				}
+				conditions[i] = n
+			}
+
+			return and(conditions...), nil
+		})
+		if err != nil {
+			return examples, err
+		}
+	}
+
+	return examples, unsupported
+}
+
+// condition is a condition of an "if" that must be true, or false, to reach
+// a part of the rule.
+type condition struct {
+	rule  any
+	path  jsonlogic.Path
+	holds bool
+}
+
+// branch is a branch of an "if" with the conditions taking it, including the
+// ones of the "if" operations around it.
+type branch struct {
+	path       jsonlogic.Path
+	conditions []condition
+}
+
+// branches returns the branches of the "if" operations of the rule, in the
+// order they appear, reached under the given conditions.
+func branches(rule any, path jsonlogic.Path, context []condition) []branch {
+	var result []branch
+
+	switch value := rule.(type) {
+	case map[string]any:
+		if len(value) != 1 {
+			return nil
+		}
+
+		for operator, args := range value {
+			list, ok := args.([]any)
+			if !ok {
+				return branches(args, child(path, operator), context)
+			}
+
+			if operator != "if" && operator != "?:" {
+				for i, arg := range list {
+					result = append(result, branches(arg, child(path, operator, strconv.Itoa(i)), context)...)
+				}
+				continue
+			}
+
+			// the conditions before the current one are false
+			reached := append([]condition(nil), context...)
+			for i := 0; i < len(list); i += 2 {
+				if i == len(list)-1 {
+					p := child(path, operator, strconv.Itoa(i))
+					result = append(result, branch{path: p, conditions: reached})
+					result = append(result, branches(list[i], p, reached)...)
+					break
+				}
+
+				c := condition{rule: list[i], path: child(path, operator, strconv.Itoa(i))}
+				result = append(result, branches(list[i], c.path, reached)...)
+
+				c.holds = true
+				taken := append(append([]condition(nil), reached...), c)
+				p := child(path, operator, strconv.Itoa(i+1))
+				result = append(result, branch{path: p, conditions: taken})
+				result = append(result, branches(list[i+1], p, taken)...)
+
+				c.holds = false
+				reached = append(append([]condition(nil), reached...), c)
+			}
+		}
+	case []any:
+		for i, item := range value {
+			result = append(result, branches(item, child(path, strconv.Itoa(i)), context)...)
+		}
+	}
+
+	return result
+}
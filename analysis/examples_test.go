@@ -0,0 +1,84 @@
+package analysis_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/analysis"
+)
+
+func TestExamples(t *testing.T) {
+	rule := parse(t, `{"and": [
+		{">=": [{"var": "age"}, 18]},
+		{"in": [{"var": "country"}, ["BR", "PT"]]}
+	]}`)
+
+	examples, err := analysis.Examples(rule, analysis.Domains{"age": analysis.Number, "country": analysis.String})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []analysis.Example{
+		{Name: "true", Data: map[string]any{"age": float64(18), "country": "BR"}, Result: true},
+		{Name: "false", Data: map[string]any{"age": float64(-1), "country": ""}, Result: false},
+	}, examples)
+}
+
+func TestExamplesAreMinimal(t *testing.T) {
+	rule := parse(t, `{"or": [{"missing": ["email"]}, {"==": [{"var": "email"}, ""]}, {"var": "blocked"}]}`)
+
+	examples, err := analysis.Examples(rule, nil)
+	assert.NoError(t, err)
+
+	assert.Len(t, examples, 2)
+	assert.Equal(t, "true", examples[0].Name)
+	assert.Equal(t, map[string]any{}, examples[0].Data)
+	assert.Equal(t, "false", examples[1].Name)
+	// false == "" in JavaScript
+	assert.Equal(t, map[string]any{"email": true}, examples[1].Data)
+	assert.Nil(t, examples[1].Result)
+}
+
+func TestExamplesOfBranches(t *testing.T) {
+	rule := parse(t, `{"if": [
+		{"<": [{"var": "age"}, 13]}, "child",
+		{"<": [{"var": "age"}, 18]}, "teenager",
+		{"if": [{"==": [{"var": "member"}, true]}, "member", "adult"]}
+	]}`)
+
+	examples, err := analysis.Examples(rule, analysis.Domains{"age": analysis.Number, "member": analysis.Boolean})
+	assert.NoError(t, err)
+
+	results := map[string]any{}
+	for _, example := range examples {
+		results[example.Name] = example.Result
+
+		if example.Path != nil {
+			assert.Equal(t, "branch "+example.Path.Pointer(), example.Name)
+		}
+	}
+
+	assert.Equal(t, map[string]any{
+		"true":              "child",
+		"branch /if/1":      "child",
+		"branch /if/3":      "teenager",
+		"branch /if/4":      "adult",
+		"branch /if/4/if/1": "member",
+		"branch /if/4/if/2": "adult",
+	}, results)
+	assert.Equal(t, jsonlogic.Path{"if", "3"}, examples[2].Path)
+	assert.Equal(t, map[string]any{"age": float64(13)}, examples[2].Data)
+}
+
+func TestExamplesOfUnsupportedRules(t *testing.T) {
+	rule := parse(t, `{"if": [{">": [{"var": "total"}, 100]}, {"*": [{"var": "total"}, 0.9]}, {"var": "total"}]}`)
+
+	examples, err := analysis.Examples(rule, analysis.Domains{"total": analysis.Number})
+	assert.EqualError(t, err, `analysis: /if/1: the operator "*" is not supported`)
+
+	assert.Len(t, examples, 2)
+	assert.Equal(t, map[string]any{"total": float64(101)}, examples[0].Data)
+	assert.Equal(t, 90.9, examples[0].Result)
+	assert.Equal(t, map[string]any{"total": float64(-1)}, examples[1].Data)
+	assert.Equal(t, float64(-1), examples[1].Result)
+}
@@ -0,0 +1,438 @@
+package analysis
+
+import (
+	"fmt"
+	"strconv"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// truth is a truth value in a three-valued logic, in which unknown stands for
+// a formula reading variables not assigned yet.
+type truth int
+
+const (
+	unknown truth = iota
+	isFalse
+	isTrue
+)
+
+type nodeKind int
+
+const (
+	constantNode nodeKind = iota
+	atomNode
+	notNode
+	andNode
+	orNode
+)
+
+// node is a boolean formula over atoms, the truthiness of a rule.
+type node struct {
+	kind     nodeKind
+	value    bool
+	atom     *atom
+	children []*node
+}
+
+func constant(value bool) *node {
+	return &node{kind: constantNode, value: value}
+}
+
+func not(n *node) *node {
+	return &node{kind: notNode, children: []*node{n}}
+}
+
+func and(children ...*node) *node {
+	return &node{kind: andNode, children: children}
+}
+
+func or(children ...*node) *node {
+	return &node{kind: orNode, children: children}
+}
+
+// eval evaluates the formula with the variables assigned so far.
+func (n *node) eval(assignment map[string]any) truth {
+	switch n.kind {
+	case constantNode:
+		if n.value {
+			return isTrue
+		}
+		return isFalse
+	case atomNode:
+		return n.atom.eval(assignment)
+	case notNode:
+		switch n.children[0].eval(assignment) {
+		case isTrue:
+			return isFalse
+		case isFalse:
+			return isTrue
+		}
+		return unknown
+	case andNode:
+		result := isTrue
+		for _, child := range n.children {
+			switch child.eval(assignment) {
+			case isFalse:
+				return isFalse
+			case unknown:
+				result = unknown
+			}
+		}
+		return result
+	}
+
+	result := isFalse
+	for _, child := range n.children {
+		switch child.eval(assignment) {
+		case isTrue:
+			return isTrue
+		case unknown:
+			result = unknown
+		}
+	}
+	return result
+}
+
+// atom is an operation comparing variables to literals, evaluated with the
+// evaluator of the package jsonlogic once all its variables are assigned.
+type atom struct {
+	rule  any
+	vars  []string
+	paths []jsonlogic.Path
+	cache map[string]bool
+}
+
+func (at *atom) eval(assignment map[string]any) truth {
+	values := make([]any, len(at.vars))
+	for i, name := range at.vars {
+		value, ok := assignment[name]
+		if !ok {
+			return unknown
+		}
+		values[i] = value
+	}
+
+	key := fmt.Sprintf("%#v", values)
+	result, ok := at.cache[key]
+	if !ok {
+		data := make(map[string]any)
+		for i := range at.vars {
+			if values[i] != absent {
+				set(data, at.paths[i], values[i])
+			}
+		}
+
+		output, err := jsonlogic.ApplyInterface(map[string]any{"!!": []any{at.rule}}, data)
+		result = err == nil && output == true
+		at.cache[key] = result
+	}
+
+	if result {
+		return isTrue
+	}
+	return isFalse
+}
+
+// build turns the rule into a formula of its truthiness.
+func (a *analyzer) build(rule any, path jsonlogic.Path) (*node, error) {
+	if len(jsonlogic.Variables(rule)) == 0 && !readsData(rule) {
+		output, err := jsonlogic.ApplyInterface(map[string]any{"!!": []any{rule}}, nil)
+		return constant(err == nil && output == true), nil
+	}
+
+	m, ok := rule.(map[string]any)
+	if !ok || len(m) != 1 {
+		return nil, &UnsupportedError{Path: path, Reason: "only operations can read the data"}
+	}
+
+	var operator string
+	for key := range m {
+		operator = key
+	}
+
+	args, ok := m[operator].([]any)
+	argPath := func(i int) jsonlogic.Path {
+		if !ok {
+			return child(path, operator)
+		}
+		return child(path, operator, strconv.Itoa(i))
+	}
+	if !ok {
+		args = []any{m[operator]}
+	}
+
+	switch operator {
+	case "and", "or":
+		children := make([]*node, len(args))
+		for i, arg := range args {
+			n, err := a.build(arg, argPath(i))
+			if err != nil {
+				return nil, err
+			}
+			children[i] = n
+		}
+		if operator == "and" {
+			return and(children...), nil
+		}
+		return or(children...), nil
+	case "!", "!!":
+		n, err := a.build(args[0], argPath(0))
+		if err != nil {
+			return nil, err
+		}
+		if operator == "!" {
+			return not(n), nil
+		}
+		return n, nil
+	case "if", "?:":
+		return a.buildConditional(args, argPath)
+	case "==", "!=", "===", "!==", "<", "<=", ">", ">=", "in", "var":
+		return a.buildAtom(rule, operator, args, path, argPath)
+	case "missing", "missing_some":
+		return a.buildMissing(rule, operator, args, path)
+	}
+
+	return nil, &UnsupportedError{Path: path, Reason: fmt.Sprintf("the operator \"%s\" is not supported", operator)}
+}
+
+// buildConditional turns "if" into the disjunction of its branches, each one
+// holding when its condition is the first one true.
+func (a *analyzer) buildConditional(args []any, argPath func(int) jsonlogic.Path) (*node, error) {
+	var branches []*node
+	var previous []*node
+
+	for i := 0; i < len(args); i += 2 {
+		if i == len(args)-1 {
+			otherwise, err := a.build(args[i], argPath(i))
+			if err != nil {
+				return nil, err
+			}
+			branches = append(branches, and(append(negated(previous), otherwise)...))
+			break
+		}
+
+		condition, err := a.build(args[i], argPath(i))
+		if err != nil {
+			return nil, err
+		}
+		then, err := a.build(args[i+1], argPath(i+1))
+		if err != nil {
+			return nil, err
+		}
+
+		branches = append(branches, and(append(negated(previous), condition, then)...))
+		previous = append(previous, condition)
+	}
+
+	return or(branches...), nil
+}
+
+func negated(nodes []*node) []*node {
+	result := make([]*node, len(nodes))
+	for i, n := range nodes {
+		result[i] = not(n)
+	}
+	return result
+}
+
+// buildAtom accepts comparisons between literals and variables with a
+// literal path, and variables read for their truthiness.
+func (a *analyzer) buildAtom(rule any, operator string, args []any, path jsonlogic.Path, argPath func(int) jsonlogic.Path) (*node, error) {
+	if operator == "var" {
+		name, err := a.variable(rule, path)
+		if err != nil {
+			return nil, err
+		}
+		return a.atom(rule, []string{name}), nil
+	}
+
+	var vars []string
+	var literals []any
+
+	for i, arg := range args {
+		if isLiteral(arg) {
+			literals = append(literals, arg)
+			continue
+		}
+
+		name, err := a.variable(arg, argPath(i))
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, name)
+	}
+
+	for _, name := range vars {
+		a.constrain(name, vars, literals)
+	}
+
+	if operator == "in" && len(args) == 2 && isLiteral(args[0]) && !isLiteral(args[1]) {
+		if needle, ok := args[0].(string); ok {
+			a.variables[vars[0]].substrings = appendUnique(a.variables[vars[0]].substrings, needle)
+		}
+	}
+	if operator != "in" && operator != "==" && operator != "!=" && operator != "===" && operator != "!==" {
+		for _, name := range vars {
+			a.variables[name].ordered = true
+		}
+	}
+
+	return a.atom(rule, vars), nil
+}
+
+func (a *analyzer) buildMissing(rule any, operator string, args []any, path jsonlogic.Path) (*node, error) {
+	keys := args
+	if operator == "missing_some" {
+		if len(args) != 2 || !isLiteral(args[0]) {
+			return nil, &UnsupportedError{Path: path, Reason: "missing_some must have a literal minimum and a list of paths"}
+		}
+		list, ok := args[1].([]any)
+		if !ok {
+			return nil, &UnsupportedError{Path: path, Reason: "missing_some must have a literal minimum and a list of paths"}
+		}
+		keys = list
+	} else if len(args) == 1 {
+		if list, ok := args[0].([]any); ok {
+			keys = list
+		}
+	}
+
+	vars := make([]string, 0, len(keys))
+	for _, key := range keys {
+		name, err := a.variable(map[string]any{"var": key}, path)
+		if err != nil {
+			return nil, err
+		}
+		vars = append(vars, name)
+	}
+
+	return a.atom(rule, vars), nil
+}
+
+func (a *analyzer) atom(rule any, vars []string) *node {
+	paths := make([]jsonlogic.Path, len(vars))
+	for i, name := range vars {
+		paths[i] = a.variables[name].path
+	}
+
+	return &node{kind: atomNode, atom: &atom{rule: rule, vars: vars, paths: paths, cache: make(map[string]bool)}}
+}
+
+// variable returns the name of the variable read by the operand, which must
+// be a "var" with a literal path and, optionally, a literal default value.
+func (a *analyzer) variable(operand any, path jsonlogic.Path) (string, error) {
+	unsupported := &UnsupportedError{Path: path, Reason: "the operands must be literals or variables with a literal path"}
+
+	m, ok := operand.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", unsupported
+	}
+
+	values, ok := m["var"]
+	if !ok {
+		return "", unsupported
+	}
+
+	var defaults []any
+	if list, ok := values.([]any); ok {
+		if len(list) == 0 || len(list) > 2 {
+			return "", unsupported
+		}
+		values, defaults = list[0], list[1:]
+	}
+
+	for _, value := range defaults {
+		if !isLiteral(value) {
+			return "", unsupported
+		}
+	}
+
+	p, ok := jsonlogic.ParsePath(values)
+	if !ok || len(p) == 0 || p.HasWildcard() {
+		return "", unsupported
+	}
+
+	name := p.String()
+	if err := a.declare(name, p, path); err != nil {
+		return "", err
+	}
+
+	a.constrain(name, []string{name}, defaults)
+
+	return name, nil
+}
+
+// readsData reports whether the rule reads the whole data, which
+// jsonlogic.Variables doesn't report.
+func readsData(rule any) bool {
+	switch value := rule.(type) {
+	case map[string]any:
+		for operator, args := range value {
+			if operator == "var" {
+				if p, ok := jsonlogic.ParsePath(args); ok && len(p) == 0 {
+					return true
+				}
+				if list, ok := args.([]any); ok && len(list) == 0 {
+					return true
+				}
+			}
+			if readsData(args) {
+				return true
+			}
+		}
+	case []any:
+		for _, item := range value {
+			if readsData(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// isLiteral reports whether the value is a primitive or an array of primitives.
+func isLiteral(value any) bool {
+	switch v := value.(type) {
+	case nil, bool, float64, string:
+		return true
+	case []any:
+		for _, item := range v {
+			switch item.(type) {
+			case nil, bool, float64, string:
+			default:
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+func child(path jsonlogic.Path, keys ...string) jsonlogic.Path {
+	return append(path[:len(path):len(path)], keys...)
+}
+
+// set stores the value at the path, creating the objects on the way.
+func set(data map[string]any, path jsonlogic.Path, value any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := data[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			data[key] = next
+		}
+		data = next
+	}
+	data[path[len(path)-1]] = value
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, v := range values {
+		if v == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
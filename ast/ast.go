@@ -0,0 +1,341 @@
+// Package ast parses rules into typed nodes, to be inspected and rewritten
+// without handling map[string]any and []any:
+//
+//	root, err := ast.Parse(rule)
+//	if err != nil {
+//		return err
+//	}
+//
+//	ast.Inspect(root, func(n ast.Node) bool {
+//		if v, ok := n.(*ast.Var); ok {
+//			path, _ := v.Path()
+//			fmt.Println(v.Location().Pointer(), path)
+//		}
+//		return true
+//	})
+//
+// The nodes follow the evaluation of rules: objects with a single key are
+// operations, and the other objects are literals whose values are never
+// evaluated. Every node records its location in the rule parsed, and encodes
+// back to the rule in canonical form, where the arguments of operations are
+// always in an array unless the single argument of an operation was written
+// without one, which makes the operator use the elements of its result as
+// arguments.
+package ast
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// Node is a part of a rule.
+type Node interface {
+	// Location returns the location of the node in the rule parsed, which
+	// is nil for the nodes built by hand.
+	Location() jsonlogic.Path
+
+	// Rule returns the node in canonical form, as the value encoding/json
+	// decodes from its JSON.
+	Rule() any
+
+	json.Marshaler
+}
+
+// Literal is a null, boolean, number or string.
+type Literal struct {
+	At    jsonlogic.Path
+	Value any
+}
+
+// Array is an array, whose items are evaluated.
+type Array struct {
+	At    jsonlogic.Path
+	Items []Node
+}
+
+// ObjectLiteral is an object with no key or more than one, returned as it is
+// by the evaluation, without evaluating its values.
+type ObjectLiteral struct {
+	At    jsonlogic.Path
+	Value map[string]any
+}
+
+// Var reads a value of the data with "var". Name is the path, usually a
+// *Literal, and Default the value returned when the path is missing, or nil.
+// The "val" operations are *Op.
+type Var struct {
+	At      jsonlogic.Path
+	Name    Node
+	Default Node
+
+	// Extra holds the arguments after the name when there are more than
+	// two, like in {"var": ["a", 1, 2]}, which has no default: they're
+	// evaluated, and their results ignored.
+	Extra []Node
+}
+
+// Let binds names to the results of the expressions in Bindings for the Body.
+type Let struct {
+	At       jsonlogic.Path
+	Bindings map[string]Node
+	Body     Node
+}
+
+// Op is an operation of any operator other than "var", and other than "let"
+// when its arguments are an object of bindings and a body.
+type Op struct {
+	At       jsonlogic.Path
+	Operator string
+	Args     []Node
+
+	// Unwrapped tells whether the single argument was written without an
+	// array, like in {"merge": {"var": "lists"}}.
+	Unwrapped bool
+}
+
+func (n *Literal) Location() jsonlogic.Path       { return n.At }
+func (n *Array) Location() jsonlogic.Path         { return n.At }
+func (n *ObjectLiteral) Location() jsonlogic.Path { return n.At }
+func (n *Var) Location() jsonlogic.Path           { return n.At }
+func (n *Let) Location() jsonlogic.Path           { return n.At }
+func (n *Op) Location() jsonlogic.Path            { return n.At }
+
+func (n *Literal) Rule() any {
+	return n.Value
+}
+
+func (n *Array) Rule() any {
+	return rules(n.Items)
+}
+
+func (n *ObjectLiteral) Rule() any {
+	if n.Value == nil {
+		return map[string]any{}
+	}
+	return n.Value
+}
+
+func (n *Var) Rule() any {
+	switch {
+	case len(n.Extra) > 0:
+		args := []any{n.Name.Rule()}
+		if n.Default != nil {
+			args = append(args, n.Default.Rule())
+		}
+		return map[string]any{"var": append(args, rules(n.Extra)...)}
+	case n.Name == nil:
+		return map[string]any{"var": []any{}}
+	case n.Default != nil:
+		return map[string]any{"var": []any{n.Name.Rule(), n.Default.Rule()}}
+	}
+
+	name := n.Name.Rule()
+	if _, ok := name.([]any); ok {
+		// an array of segments on its own would be taken as the arguments
+		return map[string]any{"var": []any{name}}
+	}
+	return map[string]any{"var": name}
+}
+
+func (n *Let) Rule() any {
+	bindings := make(map[string]any, len(n.Bindings))
+	for name, binding := range n.Bindings {
+		bindings[name] = binding.Rule()
+	}
+
+	var body any
+	if n.Body != nil {
+		body = n.Body.Rule()
+	}
+
+	return map[string]any{"let": []any{bindings, body}}
+}
+
+func (n *Op) Rule() any {
+	if n.Unwrapped && len(n.Args) == 1 {
+		return map[string]any{n.Operator: n.Args[0].Rule()}
+	}
+	return map[string]any{n.Operator: rules(n.Args)}
+}
+
+func rules(nodes []Node) []any {
+	values := make([]any, len(nodes))
+	for i, node := range nodes {
+		values[i] = node.Rule()
+	}
+	return values
+}
+
+func (n *Literal) MarshalJSON() ([]byte, error)       { return marshal(n) }
+func (n *Array) MarshalJSON() ([]byte, error)         { return marshal(n) }
+func (n *ObjectLiteral) MarshalJSON() ([]byte, error) { return marshal(n) }
+func (n *Var) MarshalJSON() ([]byte, error)           { return marshal(n) }
+func (n *Let) MarshalJSON() ([]byte, error)           { return marshal(n) }
+func (n *Op) MarshalJSON() ([]byte, error)            { return marshal(n) }
+
+func marshal(n Node) ([]byte, error) {
+	return json.Marshal(n.Rule())
+}
+
+// Path returns the path read by the variable, or false when it's computed by
+// an operation.
+func (n *Var) Path() (jsonlogic.Path, bool) {
+	switch n.Name.(type) {
+	case nil:
+		return jsonlogic.Path{}, true
+	case *Literal, *Array:
+		return jsonlogic.ParsePath(n.Name.Rule())
+	}
+
+	return nil, false
+}
+
+// Names returns the names bound, sorted.
+func (n *Let) Names() []string {
+	names := make([]string, 0, len(n.Bindings))
+	for name := range n.Bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Parse parses a rule, as decoded by encoding/json.
+//
+// Parameters:
+//   - rule: interface{} representing the rule to be parsed
+//
+// Returns:
+//   - node: the root of the rule
+//   - err: error when the rule holds values of types encoding/json doesn't produce
+func Parse(rule any) (Node, error) {
+	return parse(rule, jsonlogic.Path{})
+}
+
+// ParseRaw parses a rule encoded as JSON.
+func ParseRaw(rule json.RawMessage) (Node, error) {
+	var decoded any
+	if err := json.Unmarshal(rule, &decoded); err != nil {
+		return nil, err
+	}
+
+	return Parse(decoded)
+}
+
+func parse(rule any, at jsonlogic.Path) (Node, error) {
+	switch value := rule.(type) {
+	case nil, bool, float64, string:
+		return &Literal{At: at, Value: value}, nil
+	case []any:
+		items, err := parseAll(value, at)
+		if err != nil {
+			return nil, err
+		}
+		return &Array{At: at, Items: items}, nil
+	case map[string]any:
+		if len(value) != 1 {
+			return &ObjectLiteral{At: at, Value: value}, nil
+		}
+		for operator, args := range value {
+			return parseOperation(operator, args, at)
+		}
+	}
+
+	return nil, fmt.Errorf("ast: %s: unsupported type %T", location(at), rule)
+}
+
+func parseAll(values []any, at jsonlogic.Path) ([]Node, error) {
+	nodes := make([]Node, len(values))
+	for i, v := range values {
+		node, err := parse(v, child(at, strconv.Itoa(i)))
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+func parseOperation(operator string, values any, at jsonlogic.Path) (Node, error) {
+	args, isList := values.([]any)
+
+	switch {
+	case operator == "var":
+		n := &Var{At: at}
+		if !isList {
+			name, err := parse(values, child(at, operator))
+			if err != nil {
+				return nil, err
+			}
+			n.Name = name
+			return n, nil
+		}
+		nodes, err := parseAll(args, child(at, operator))
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case len(nodes) > 2:
+			n.Name, n.Extra = nodes[0], nodes[1:]
+		case len(nodes) == 2:
+			n.Name, n.Default = nodes[0], nodes[1]
+		case len(nodes) == 1:
+			n.Name = nodes[0]
+		}
+		return n, nil
+	case operator == "let" && isList && len(args) == 2:
+		bindings, ok := args[0].(map[string]any)
+		if !ok {
+			break
+		}
+		n := &Let{At: at, Bindings: make(map[string]Node, len(bindings))}
+		for name, binding := range bindings {
+			node, err := parse(binding, child(at, operator, "0", name))
+			if err != nil {
+				return nil, err
+			}
+			n.Bindings[name] = node
+		}
+		body, err := parse(args[1], child(at, operator, "1"))
+		if err != nil {
+			return nil, err
+		}
+		n.Body = body
+		return n, nil
+	}
+
+	n := &Op{At: at, Operator: operator}
+	if !isList {
+		arg, err := parse(values, child(at, operator))
+		if err != nil {
+			return nil, err
+		}
+		n.Args, n.Unwrapped = []Node{arg}, true
+		return n, nil
+	}
+
+	nodes, err := parseAll(args, child(at, operator))
+	if err != nil {
+		return nil, err
+	}
+	n.Args = nodes
+
+	return n, nil
+}
+
+// child returns a copy of the path extended with the keys.
+func child(path jsonlogic.Path, keys ...string) jsonlogic.Path {
+	return append(path[:len(path):len(path)], keys...)
+}
+
+// location returns the path as a JSON Pointer, or "(root)" when it's empty.
+func location(p jsonlogic.Path) string {
+	if len(p) == 0 {
+		return "(root)"
+	}
+	return p.Pointer()
+}
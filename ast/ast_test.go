@@ -0,0 +1,178 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/ast"
+)
+
+func parse(t *testing.T, rule string) ast.Node {
+	t.Helper()
+
+	node, err := ast.ParseRaw(json.RawMessage(rule))
+	require.NoError(t, err)
+
+	return node
+}
+
+func TestParse(t *testing.T) {
+	root := parse(t, `{"and": [{">=": [{"var": "age"}, 18]}, {"in": ["admin", {"var": ["roles", []]}]}]}`)
+
+	and, ok := root.(*ast.Op)
+	require.True(t, ok)
+	assert.Equal(t, "and", and.Operator)
+	assert.Len(t, and.Args, 2)
+	assert.Equal(t, jsonlogic.Path{}, and.Location())
+
+	gte := and.Args[0].(*ast.Op)
+	assert.Equal(t, jsonlogic.Path{"and", "0"}, gte.Location())
+
+	age := gte.Args[0].(*ast.Var)
+	assert.Equal(t, "/and/0/>=/0", age.Location().Pointer())
+	assert.Equal(t, &ast.Literal{At: jsonlogic.Path{"and", "0", ">=", "0", "var"}, Value: "age"}, age.Name)
+	assert.Nil(t, age.Default)
+
+	roles := and.Args[1].(*ast.Op).Args[1].(*ast.Var)
+	path, ok := roles.Path()
+	assert.True(t, ok)
+	assert.Equal(t, jsonlogic.Path{"roles"}, path)
+	assert.Equal(t, &ast.Array{At: jsonlogic.Path{"and", "1", "in", "1", "var", "1"}, Items: []ast.Node{}}, roles.Default)
+}
+
+func TestParseObjects(t *testing.T) {
+	scenarios := map[string]struct {
+		rule     string
+		expected func(t *testing.T, node ast.Node)
+	}{
+		"object literal": {
+			rule: `{"a": {"var": "x"}, "b": 1}`,
+			expected: func(t *testing.T, node ast.Node) {
+				assert.Equal(t, &ast.ObjectLiteral{At: jsonlogic.Path{}, Value: map[string]any{"a": map[string]any{"var": "x"}, "b": 1.0}}, node)
+			},
+		},
+		"empty object": {
+			rule: `{}`,
+			expected: func(t *testing.T, node ast.Node) {
+				assert.IsType(t, &ast.ObjectLiteral{}, node)
+			},
+		},
+		"unknown operator": {
+			rule: `{"slugify": {"var": "title"}}`,
+			expected: func(t *testing.T, node ast.Node) {
+				op := node.(*ast.Op)
+				assert.Equal(t, "slugify", op.Operator)
+				assert.True(t, op.Unwrapped)
+				assert.IsType(t, &ast.Var{}, op.Args[0])
+			},
+		},
+		"let with a single binding": {
+			rule: `{"let": [{"total": {"+": [1, 2]}}, {"var": "total"}]}`,
+			expected: func(t *testing.T, node ast.Node) {
+				let := node.(*ast.Let)
+				assert.Equal(t, []string{"total"}, let.Names())
+				assert.Equal(t, "/let/0/total", let.Bindings["total"].Location().Pointer())
+				assert.IsType(t, &ast.Op{}, let.Bindings["total"])
+				assert.IsType(t, &ast.Var{}, let.Body)
+			},
+		},
+		"computed var": {
+			rule: `{"var": {"cat": ["user.", {"var": "field"}]}}`,
+			expected: func(t *testing.T, node ast.Node) {
+				_, ok := node.(*ast.Var).Path()
+				assert.False(t, ok)
+			},
+		},
+		"var with segments": {
+			rule: `{"var": [["user", "e-mail.address"]]}`,
+			expected: func(t *testing.T, node ast.Node) {
+				path, ok := node.(*ast.Var).Path()
+				assert.True(t, ok)
+				assert.Equal(t, jsonlogic.Path{"user", "e-mail.address"}, path)
+			},
+		},
+		"var with too many arguments": {
+			rule: `{"var": ["a", 1, {"var": "b"}]}`,
+			expected: func(t *testing.T, node ast.Node) {
+				v := node.(*ast.Var)
+				path, ok := v.Path()
+				assert.True(t, ok)
+				assert.Equal(t, jsonlogic.Path{"a"}, path)
+				assert.Nil(t, v.Default)
+				assert.Len(t, v.Extra, 2)
+				assert.Equal(t, jsonlogic.Path{"var", "2"}, v.Extra[1].Location())
+			},
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			scenario.expected(t, parse(t, scenario.rule))
+		})
+	}
+}
+
+func TestParseUnsupportedType(t *testing.T) {
+	_, err := ast.Parse(map[string]any{"==": []any{1.0, 2}})
+	assert.EqualError(t, err, "ast: /==/1: unsupported type int")
+
+	_, err = ast.ParseRaw(json.RawMessage(`{"var": `))
+	assert.Error(t, err)
+}
+
+func TestMarshalJSON(t *testing.T) {
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"unchanged":      {rule: `{"<": [0, {"var": "x"}, 10]}`, expected: `{"<":[0,{"var":"x"},10]}`},
+		"single var":     {rule: `{"var": ["x"]}`, expected: `{"var":"x"}`},
+		"empty var":      {rule: `{"var": []}`, expected: `{"var":[]}`},
+		"var segments":   {rule: `{"var": [["a", 0]]}`, expected: `{"var":[["a",0]]}`},
+		"var extra":      {rule: `{"var": ["a", 1, 2]}`, expected: `{"var":["a",1,2]}`},
+		"unwrapped":      {rule: `{"merge": {"var": "lists"}}`, expected: `{"merge":{"var":"lists"}}`},
+		"object literal": {rule: `{"a": 1, "b": {"var": "x"}}`, expected: `{"a":1,"b":{"var":"x"}}`},
+		"let":            {rule: `{"let": [{"a": 1, "b": 2}, {"+": [{"var": "a"}, {"var": "b"}]}]}`, expected: `{"let":[{"a":1,"b":2},{"+":[{"var":"a"},{"var":"b"}]}]}`},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.Marshal(parse(t, scenario.rule))
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, string(encoded))
+		})
+	}
+}
+
+func TestRuleEvaluatesLikeTheSource(t *testing.T) {
+	rules := []string{
+		`{"if": [{">=": [{"var": "age"}, 18]}, "adult", "minor"]}`,
+		`{"merge": {"var": "lists"}}`,
+		`{"let": [{"double": {"*": [{"var": "age"}, 2]}}, {"var": "double"}]}`,
+		`{"map": [{"var": "lists"}, {"merge": [{"var": ""}, 0]}]}`,
+		`{"var": ["missing", 1, 2]}`,
+	}
+	data := `{"age": 20, "lists": [[1, 2], [3]]}`
+
+	for _, rule := range rules {
+		t.Run(rule, func(t *testing.T) {
+			var expected strings.Builder
+			err := jsonlogic.Apply(strings.NewReader(rule), strings.NewReader(data), &expected)
+			require.NoError(t, err)
+
+			encoded, err := json.Marshal(parse(t, rule))
+			require.NoError(t, err)
+
+			var result strings.Builder
+			err = jsonlogic.Apply(strings.NewReader(string(encoded)), strings.NewReader(data), &result)
+			require.NoError(t, err)
+
+			assert.Equal(t, expected.String(), result.String())
+		})
+	}
+}
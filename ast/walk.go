@@ -0,0 +1,132 @@
+package ast
+
+// Visitor visits the nodes walked by Walk. When Visit returns a non-nil
+// visitor w, Walk visits the children of the node with w, followed by a call
+// of w.Visit(nil).
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree in depth-first order, visiting the arguments of
+// operations in order and the bindings of "let" sorted by name, before its
+// body.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	for _, c := range children(node) {
+		Walk(v, c)
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the tree like Walk, calling f for every node and then
+// f(nil) after its children. The children of a node are skipped when f
+// returns false for it.
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+// children returns the children of the node, in the order they're walked.
+func children(node Node) []Node {
+	switch n := node.(type) {
+	case *Array:
+		return n.Items
+	case *Op:
+		return n.Args
+	case *Var:
+		var nodes []Node
+		if n.Name != nil {
+			nodes = append(nodes, n.Name)
+		}
+		if n.Default != nil {
+			nodes = append(nodes, n.Default)
+		}
+		return append(nodes, n.Extra...)
+	case *Let:
+		nodes := make([]Node, 0, len(n.Bindings)+1)
+		for _, name := range n.Names() {
+			nodes = append(nodes, n.Bindings[name])
+		}
+		if n.Body != nil {
+			nodes = append(nodes, n.Body)
+		}
+		return nodes
+	}
+
+	return nil
+}
+
+// Rewrite returns a copy of the tree where every node is replaced by the
+// result of f, from the leaves up: f receives the nodes with their children
+// already rewritten, and returns them to keep them. The tree given is left
+// untouched.
+//
+//	// replace the "==" operations by "==="
+//	strict := ast.Rewrite(root, func(n ast.Node) ast.Node {
+//		if op, ok := n.(*ast.Op); ok && op.Operator == "==" {
+//			op.Operator = "==="
+//		}
+//		return n
+//	})
+func Rewrite(node Node, f func(Node) Node) Node {
+	if node == nil {
+		return nil
+	}
+
+	switch n := node.(type) {
+	case *Literal:
+		c := *n
+		node = &c
+	case *ObjectLiteral:
+		c := *n
+		node = &c
+	case *Array:
+		c := *n
+		c.Items = rewriteAll(n.Items, f)
+		node = &c
+	case *Op:
+		c := *n
+		c.Args = rewriteAll(n.Args, f)
+		node = &c
+	case *Var:
+		c := *n
+		c.Name = Rewrite(n.Name, f)
+		c.Default = Rewrite(n.Default, f)
+		c.Extra = rewriteAll(n.Extra, f)
+		node = &c
+	case *Let:
+		c := *n
+		c.Bindings = make(map[string]Node, len(n.Bindings))
+		for _, name := range n.Names() {
+			c.Bindings[name] = Rewrite(n.Bindings[name], f)
+		}
+		c.Body = Rewrite(n.Body, f)
+		node = &c
+	}
+
+	return f(node)
+}
+
+func rewriteAll(nodes []Node, f func(Node) Node) []Node {
+	if nodes == nil {
+		return nil
+	}
+
+	rewritten := make([]Node, len(nodes))
+	for i, n := range nodes {
+		rewritten[i] = Rewrite(n, f)
+	}
+	return rewritten
+}
@@ -0,0 +1,116 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/diegoholiveira/jsonlogic/v3/ast"
+)
+
+type recorder struct {
+	visited *[]string
+}
+
+func (r recorder) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		*r.visited = append(*r.visited, "end")
+		return nil
+	}
+
+	*r.visited = append(*r.visited, fmt.Sprintf("%T %s", node, node.Location().Pointer()))
+	return r
+}
+
+func TestWalk(t *testing.T) {
+	root := parse(t, `{"let": [{"b": 2, "a": [1]}, {"var": "a"}]}`)
+
+	var visited []string
+	ast.Walk(recorder{&visited}, root)
+
+	assert.Equal(t, []string{
+		"*ast.Let ",
+		"*ast.Array /let/0/a",
+		"*ast.Literal /let/0/a/0",
+		"end",
+		"end",
+		"*ast.Literal /let/0/b",
+		"end",
+		"*ast.Var /let/1",
+		"*ast.Literal /let/1/var",
+		"end",
+		"end",
+		"end",
+	}, visited)
+}
+
+func TestInspect(t *testing.T) {
+	root := parse(t, `{"and": [{"var": "a"}, {"some": [{"var": "items"}, {"var": "price"}]}, {"a": {"var": "b"}, "c": 1}]}`)
+
+	var names []string
+	ast.Inspect(root, func(n ast.Node) bool {
+		if op, ok := n.(*ast.Op); ok && op.Operator == "some" {
+			return false
+		}
+		if v, ok := n.(*ast.Var); ok {
+			path, _ := v.Path()
+			names = append(names, path.String())
+		}
+		return true
+	})
+
+	assert.Equal(t, []string{"a"}, names)
+}
+
+func TestRewrite(t *testing.T) {
+	root := parse(t, `{"and": [{"==": [{"var": "age"}, "18"]}, {"==": [{"var": "country"}, "BR"]}]}`)
+
+	rewritten := ast.Rewrite(root, func(n ast.Node) ast.Node {
+		switch n := n.(type) {
+		case *ast.Op:
+			if n.Operator == "==" {
+				n.Operator = "==="
+			}
+		case *ast.Var:
+			if path, ok := n.Path(); ok && path.String() == "age" {
+				return &ast.Var{At: n.At, Name: &ast.Literal{Value: "user.age"}}
+			}
+		}
+		return n
+	})
+
+	encoded, err := json.Marshal(rewritten)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"and": [{"===": [{"var": "user.age"}, "18"]}, {"===": [{"var": "country"}, "BR"]}]}`, string(encoded))
+
+	original, err := json.Marshal(root)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"and": [{"==": [{"var": "age"}, "18"]}, {"==": [{"var": "country"}, "BR"]}]}`, string(original))
+}
+
+func TestRewriteFromTheLeaves(t *testing.T) {
+	root := parse(t, `{"+": [1, {"+": [2, 3]}]}`)
+
+	// folds the sums of literals, which needs the arguments folded first
+	folded := ast.Rewrite(root, func(n ast.Node) ast.Node {
+		op, ok := n.(*ast.Op)
+		if !ok || op.Operator != "+" {
+			return n
+		}
+
+		sum := 0.0
+		for _, arg := range op.Args {
+			literal, ok := arg.(*ast.Literal)
+			if !ok {
+				return n
+			}
+			sum += literal.Value.(float64)
+		}
+
+		return &ast.Literal{At: op.At, Value: sum}
+	})
+
+	assert.Equal(t, 6.0, folded.Rule())
+}
@@ -0,0 +1,51 @@
+// Command jsonlogic works with JsonLogic rules from the command line.
+//
+// Usage:
+//
+//	jsonlogic test [-tags tag,...] [-v] [path ...]
+//
+// The test command runs test files, described in the jsonlogictest package,
+// against the library. Directories are searched recursively for the files
+// ending in _test.yaml, _test.yml or _test.json, and the current directory is
+// searched when no path is given. It exits with status 1 when a test fails.
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// command runs a subcommand with its arguments and returns the exit status.
+type command func(args []string, stdout, stderr io.Writer) int
+
+var commands = map[string]command{
+	"test": runTests,
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr))
+}
+
+func run(args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 {
+		usage(stderr)
+		return 2
+	}
+
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "jsonlogic: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	return cmd(args[1:], stdout, stderr)
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: jsonlogic <command> [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	fmt.Fprintln(w, "  test    run the test files of rules")
+}
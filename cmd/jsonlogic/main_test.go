@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+
+	assert.Equal(t, 2, run(nil, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "usage: jsonlogic <command> [arguments]")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"lint"}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), `jsonlogic: unknown command "lint"`)
+}
+
+func TestRunTests(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) {
+		path := filepath.Join(dir, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+
+	write("rules/adult.json", `{">=": [{"var": "age"}, 18]}`)
+	write("rules/adult_test.yaml", `
+tests:
+  - description: adults
+    ref: adult.json
+    tags: [smoke]
+    cases:
+      - {data: {age: 17}, expected: false}
+      - {data: {age: 18}, expected: true}
+`)
+	write("other/limits_test.json", `{"tests": [{"rule": {"max": [1, 2]}, "expected": 3}]}`)
+	write("other/ignored.json", `{"tests": "not a test file"}`)
+
+	var stdout, stderr bytes.Buffer
+	assert.Equal(t, 1, run([]string{"test", dir}, &stdout, &stderr))
+	assert.Empty(t, stderr.String())
+	assert.Equal(t, filepath.Join(dir, "other/limits_test.json")+`: 0 passed, 1 failed
+test 0#0: applying {"max":[1,2]} to null
+(root): expected 3, got 2
+FAIL: 2 passed, 1 failed
+`, stdout.String())
+
+	stdout.Reset()
+	assert.Equal(t, 0, run([]string{"test", "-tags", "smoke", "-v", dir}, &stdout, &stderr))
+	assert.Contains(t, stdout.String(), filepath.Join(dir, "rules/adult_test.yaml")+": 2 passed, 0 failed\n")
+	assert.Contains(t, stdout.String(), "ok: 2 passed, 0 failed\n")
+
+	assert.Equal(t, 2, run([]string{"test", filepath.Join(dir, "other/ignored.json")}, &stdout, &stderr))
+	assert.Contains(t, stderr.String(), "jsonlogic: jsonlogictest: tests ignored:")
+
+	stderr.Reset()
+	assert.Equal(t, 2, run([]string{"test", filepath.Join(dir, "missing")}, &stdout, &stderr))
+	assert.NotEmpty(t, stderr.String())
+}
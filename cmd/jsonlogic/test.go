@@ -0,0 +1,122 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/jsonlogictest"
+)
+
+// testFileSuffixes are the endings of the test files searched in directories.
+var testFileSuffixes = []string{"_test.yaml", "_test.yml", "_test.json"}
+
+func runTests(args []string, stdout, stderr io.Writer) int {
+	flags := flag.NewFlagSet("test", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	tags := flags.String("tags", "", "run only the tests having one of the comma-separated `tags`")
+	verbose := flags.Bool("v", false, "report the files whose tests all pass too")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	paths, err := findTestFiles(flags.Args())
+	if err != nil {
+		fmt.Fprintf(stderr, "jsonlogic: %s\n", err)
+		return 2
+	}
+
+	var wanted []string
+	if *tags != "" {
+		wanted = strings.Split(*tags, ",")
+	}
+
+	passed, failed := 0, 0
+	for _, path := range paths {
+		suite, err := jsonlogictest.LoadTestFile(path)
+		if err != nil {
+			fmt.Fprintf(stderr, "jsonlogic: %s\n", err)
+			return 2
+		}
+		suite.Name = path
+
+		report := jsonlogictest.Run(suite.Filter(wanted...), jsonlogic.ApplyInterface)
+		passed += report.Passed
+		failed += len(report.Failures)
+
+		if *verbose || !report.OK() {
+			fmt.Fprintln(stdout, report)
+		}
+	}
+
+	status := "ok"
+	if failed > 0 {
+		status = "FAIL"
+	}
+	fmt.Fprintf(stdout, "%s: %d passed, %d failed\n", status, passed, failed)
+
+	if failed > 0 {
+		return 1
+	}
+
+	return 0
+}
+
+// findTestFiles returns the files given and the test files in the
+// directories given, or in the current directory when none is given.
+func findTestFiles(args []string) ([]string, error) {
+	if len(args) == 0 {
+		args = []string{"."}
+	}
+
+	var paths []string
+	for _, arg := range args {
+		info, err := os.Stat(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		if !info.IsDir() {
+			paths = append(paths, arg)
+			continue
+		}
+
+		var found []string
+		err = filepath.WalkDir(arg, func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && isTestFile(path) {
+				found = append(found, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		sort.Strings(found)
+		paths = append(paths, found...)
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no test file found in %s", strings.Join(args, ", "))
+	}
+
+	return paths, nil
+}
+
+func isTestFile(path string) bool {
+	for _, suffix := range testFileSuffixes {
+		if strings.HasSuffix(path, suffix) {
+			return true
+		}
+	}
+	return false
+}
@@ -2,8 +2,8 @@ package jsonlogic
 
 import "github.com/diegoholiveira/jsonlogic/v3/internal/javascript"
 
-func hardEquals(values, data any) any {
-	values = parseValues(values, data)
+func (e *evaluator) hardEquals(values, data any) any {
+	values = e.parseValues(values, data)
 	parsed, ok := values.([]any)
 	if !ok {
 		return false
@@ -26,8 +26,8 @@ func hardEquals(values, data any) any {
 	return equals(a, b)
 }
 
-func isLessThan(values, data any) any {
-	parsed := parseValues(values, data).([]any)
+func (e *evaluator) isLessThan(values, data any) any {
+	parsed := e.comparableOperands(values, data).([]any)
 	if len(parsed) < 2 {
 		return false
 	}
@@ -44,8 +44,8 @@ func isLessThan(values, data any) any {
 	return less(a, b)
 }
 
-func isLessOrEqualThan(values, data any) any {
-	parsed := parseValues(values, data).([]any)
+func (e *evaluator) isLessOrEqualThan(values, data any) any {
+	parsed := e.comparableOperands(values, data).([]any)
 	if len(parsed) < 2 {
 		return false
 	}
@@ -62,8 +62,8 @@ func isLessOrEqualThan(values, data any) any {
 	return less(a, b) || equals(a, b)
 }
 
-func isGreaterThan(values, data any) any {
-	parsed := parseValues(values, data).([]any)
+func (e *evaluator) isGreaterThan(values, data any) any {
+	parsed := e.comparableOperands(values, data).([]any)
 	if len(parsed) < 2 {
 		return false
 	}
@@ -79,8 +79,8 @@ func isGreaterThan(values, data any) any {
 	return less(b, a)
 }
 
-func isGreaterOrEqualThan(values, data any) any {
-	parsed := parseValues(values, data).([]any)
+func (e *evaluator) isGreaterOrEqualThan(values, data any) any {
+	parsed := e.comparableOperands(values, data).([]any)
 	if len(parsed) < 2 {
 		return false
 	}
@@ -97,8 +97,8 @@ func isGreaterOrEqualThan(values, data any) any {
 	return less(b, a) || equals(b, a)
 }
 
-func isEqual(values, data any) any {
-	parsed := parseValues(values, data).([]any)
+func (e *evaluator) isEqual(values, data any) any {
+	parsed := e.equalityOperands(values, data).([]any)
 	if len(parsed) < 2 {
 		return false
 	}
@@ -0,0 +1,310 @@
+package jsonlogic
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+
+	"github.com/diegoholiveira/jsonlogic/v3/internal/javascript"
+)
+
+// Coverage applies a rule while recording which of its operations were
+// evaluated, skipped and what they produced, aggregated over every
+// evaluation. It's safe for concurrent use.
+type Coverage struct {
+	rule any
+	opts Options
+
+	// nodes holds the operations of the rule in the order they appear, and
+	// index their position by pointer.
+	nodes []coverageNode
+	index map[uintptr]int
+
+	mu          sync.Mutex
+	evaluations int
+	stats       []NodeCoverage
+}
+
+type coverageNode struct {
+	path     Path
+	operator string
+
+	// parent is the position of the closest operation around this one, or
+	// -1 for the root of the rule.
+	parent int
+
+	// branches is the number of branches of an "if", else 0.
+	branches int
+}
+
+// NodeCoverage is the coverage of an operation of a rule.
+type NodeCoverage struct {
+	Path     Path   `json:"path"`
+	Operator string `json:"operator"`
+
+	// Evaluated counts the times the operation was evaluated, and
+	// ShortCircuited the evaluations of the operation around it in which it
+	// wasn't, like the operands of "and" after a false one or the branches
+	// of "if" not taken.
+	Evaluated      int `json:"evaluated"`
+	ShortCircuited int `json:"short_circuited"`
+
+	// True and False count the truthy and falsy results.
+	True  int `json:"true"`
+	False int `json:"false"`
+
+	// Branches counts, for "if", the times each branch was taken: one per
+	// condition, then the else branch, even when it's implicit.
+	Branches []int `json:"branches,omitempty"`
+}
+
+// CoverageReport is the coverage of a rule over a number of evaluations. It
+// encodes to JSON with encoding/json.
+type CoverageReport struct {
+	Evaluations int            `json:"evaluations"`
+	Nodes       []NodeCoverage `json:"nodes"`
+}
+
+// NewCoverage returns a Coverage applying the rule with the given options.
+func NewCoverage(rule any, opts Options) *Coverage {
+	c := &Coverage{
+		rule:  rule,
+		opts:  opts,
+		index: make(map[uintptr]int),
+	}
+
+	c.collect(rule, Path{}, -1)
+
+	c.stats = make([]NodeCoverage, len(c.nodes))
+	for i, n := range c.nodes {
+		c.stats[i] = NodeCoverage{Path: n.path, Operator: n.operator}
+		if n.branches > 0 {
+			c.stats[i].Branches = make([]int, n.branches)
+		}
+	}
+
+	return c
+}
+
+func (c *Coverage) collect(rule any, path Path, parent int) {
+	switch value := rule.(type) {
+	case map[string]any:
+		if len(value) != 1 {
+			return
+		}
+
+		for operator, args := range value {
+			position := len(c.nodes)
+			c.index[reflect.ValueOf(value).Pointer()] = position
+			c.nodes = append(c.nodes, coverageNode{path: path, operator: operator, parent: parent})
+
+			if list, ok := args.([]any); ok {
+				if operator == "if" || operator == "?:" {
+					c.nodes[position].branches = len(list)/2 + 1
+				}
+				for i, arg := range list {
+					c.collect(arg, append(path[:len(path):len(path)], operator, strconv.Itoa(i)), position)
+				}
+			} else {
+				c.collect(args, append(path[:len(path):len(path)], operator), position)
+			}
+		}
+	case []any:
+		for i, item := range value {
+			c.collect(item, append(path[:len(path):len(path)], strconv.Itoa(i)), parent)
+		}
+	}
+}
+
+// ApplyInterface works like the package-level ApplyInterfaceWithOptions, with
+// the rule and the options of the coverage, and records the evaluation.
+//
+// Parameters:
+//   - data: interface{} containing the input data to transform
+//
+// Returns:
+//   - output: interface{} containing the transformed data
+//   - err: error if unsupported types are detected or if the transformation fails
+func (c *Coverage) ApplyInterface(data any) (any, error) {
+	if err := scanForUnsupportedTypes(c.rule); err != nil {
+		return nil, err
+	}
+	if err := scanForUnsupportedTypes(data); err != nil {
+		return nil, err
+	}
+
+	run := &coverageRun{
+		coverage:  c,
+		evaluated: make([]int, len(c.nodes)),
+		truthy:    make([]int, len(c.nodes)),
+		falsy:     make([]int, len(c.nodes)),
+		branches:  make(map[int][]int),
+	}
+
+	e := newEvaluatorWithOptions(c.opts)
+	e.coverage = run
+
+	output, err := e.evaluate(c.rule, data)
+
+	c.merge(run)
+
+	return output, err
+}
+
+// ApplyRaw works like ApplyInterface with data encoded as JSON.
+//
+// Parameters:
+//   - data: json.RawMessage containing the input data to transform
+//
+// Returns:
+//   - output: json.RawMessage containing the transformed data
+//   - err: error if the transformation fails
+func (c *Coverage) ApplyRaw(data json.RawMessage) (json.RawMessage, error) {
+	if data == nil {
+		data = json.RawMessage("{}")
+	}
+
+	var _data any
+	if err := json.Unmarshal(data, &_data); err != nil {
+		return nil, err
+	}
+
+	result, err := c.ApplyInterface(_data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&result)
+}
+
+func (c *Coverage) merge(run *coverageRun) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evaluations++
+
+	for i, n := range c.nodes {
+		stats := &c.stats[i]
+
+		stats.Evaluated += run.evaluated[i]
+		stats.True += run.truthy[i]
+		stats.False += run.falsy[i]
+
+		if n.parent >= 0 && run.evaluated[i] == 0 && run.evaluated[n.parent] > 0 {
+			stats.ShortCircuited++
+		}
+
+		for branch, taken := range run.branches[i] {
+			stats.Branches[branch] += taken
+		}
+	}
+}
+
+// Report returns the coverage recorded so far.
+func (c *Coverage) Report() CoverageReport {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	nodes := make([]NodeCoverage, len(c.stats))
+	for i, stats := range c.stats {
+		nodes[i] = stats
+		nodes[i].Branches = append([]int(nil), stats.Branches...)
+	}
+
+	return CoverageReport{Evaluations: c.evaluations, Nodes: nodes}
+}
+
+// Reset discards the coverage recorded so far.
+func (c *Coverage) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.evaluations = 0
+	for i := range c.stats {
+		stats := &c.stats[i]
+		stats.Evaluated, stats.ShortCircuited, stats.True, stats.False = 0, 0, 0, 0
+		for branch := range stats.Branches {
+			stats.Branches[branch] = 0
+		}
+	}
+}
+
+// Summary counts the operations evaluated at least once and the branches
+// taken at least once.
+func (r CoverageReport) Summary() (evaluated, operations, taken, branches int) {
+	for _, node := range r.Nodes {
+		operations++
+		if node.Evaluated > 0 {
+			evaluated++
+		}
+		for _, count := range node.Branches {
+			branches++
+			if count > 0 {
+				taken++
+			}
+		}
+	}
+
+	return evaluated, operations, taken, branches
+}
+
+func (r CoverageReport) String() string {
+	evaluated, operations, taken, branches := r.Summary()
+
+	return fmt.Sprintf("%d evaluations: %d/%d operations evaluated, %d/%d branches taken", r.Evaluations, evaluated, operations, taken, branches)
+}
+
+// coverageRun records a single evaluation, merged into the coverage once done.
+type coverageRun struct {
+	coverage  *Coverage
+	evaluated []int
+	truthy    []int
+	falsy     []int
+	branches  map[int][]int
+}
+
+// position returns the position of the operation in the rule covered, or
+// false for the operations built during the evaluation.
+func (r *coverageRun) position(node map[string]any) (int, bool) {
+	position, ok := r.coverage.index[reflect.ValueOf(node).Pointer()]
+	return position, ok
+}
+
+// enter records the operation being evaluated.
+func (r *coverageRun) enter(node map[string]any) {
+	if position, ok := r.position(node); ok {
+		r.evaluated[position]++
+	}
+}
+
+// record records the result of the operation.
+func (r *coverageRun) record(node map[string]any, result any) {
+	position, ok := r.position(node)
+	if !ok {
+		return
+	}
+
+	if javascript.IsTrue(result) {
+		r.truthy[position]++
+	} else {
+		r.falsy[position]++
+	}
+}
+
+// branch records the branch taken by an "if", counting the else branch after
+// the conditions.
+func (r *coverageRun) branch(node map[string]any, branch int) {
+	position, ok := r.position(node)
+	if !ok {
+		return
+	}
+
+	taken, ok := r.branches[position]
+	if !ok {
+		taken = make([]int, r.coverage.nodes[position].branches)
+		r.branches[position] = taken
+	}
+	taken[branch]++
+}
@@ -0,0 +1,259 @@
+package jsonlogic
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// coverageLine is a line of the rule annotated with its coverage.
+type coverageLine struct {
+	Indent string
+	Text   string
+
+	// Node is the coverage of the operation starting on the line, if any,
+	// and Taken the times the line was taken as a branch of an "if".
+	Node   *NodeCoverage
+	Branch bool
+	Taken  int
+}
+
+// Annotation describes the coverage of the line.
+func (l coverageLine) Annotation() string {
+	var parts []string
+
+	if l.Branch {
+		if l.Taken == 0 {
+			parts = append(parts, "never taken")
+		} else {
+			parts = append(parts, fmt.Sprintf("taken %dx", l.Taken))
+		}
+	}
+
+	if l.Node != nil {
+		if l.Node.Evaluated == 0 {
+			parts = append(parts, "never evaluated")
+		} else {
+			parts = append(parts, fmt.Sprintf("%dx", l.Node.Evaluated), fmt.Sprintf("true %d", l.Node.True), fmt.Sprintf("false %d", l.Node.False))
+		}
+		if l.Node.ShortCircuited > 0 {
+			parts = append(parts, fmt.Sprintf("skipped %d", l.Node.ShortCircuited))
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+// Class is "uncovered" for the operations never evaluated and the branches
+// never taken, "partial" for the conditions that were never true or never
+// false, "covered" for the other annotated lines and "" for the others.
+func (l coverageLine) Class() string {
+	switch {
+	case l.Branch && l.Taken == 0, l.Node != nil && l.Node.Evaluated == 0:
+		return "uncovered"
+	case l.Node != nil && booleanOperators[l.Node.Operator] && (l.Node.True == 0 || l.Node.False == 0):
+		return "partial"
+	case l.Branch || l.Node != nil:
+		return "covered"
+	}
+
+	return ""
+}
+
+// booleanOperators are the operators used as conditions, whose coverage
+// includes producing both outcomes.
+var booleanOperators = map[string]bool{
+	"and": true, "or": true, "!": true, "!!": true, "in": true,
+	"==": true, "!=": true, "===": true, "!==": true,
+	"<": true, "<=": true, ">": true, ">=": true,
+	"some": true, "all": true, "none": true,
+}
+
+// lines lays out the rule with an operation per line, except for the
+// operations that only contain variables and literals.
+func (c *Coverage) lines(report CoverageReport) []coverageLine {
+	var lines []coverageLine
+
+	var layout func(rule any, depth int, prefix, suffix string, branch bool, taken int)
+	layout = func(rule any, depth int, prefix, suffix string, branch bool, taken int) {
+		indent := strings.Repeat("  ", depth)
+
+		var node *NodeCoverage
+		m, isOperation := rule.(map[string]any)
+		if isOperation && len(m) == 1 {
+			if position, ok := c.index[reflect.ValueOf(m).Pointer()]; ok {
+				node = &report.Nodes[position]
+			}
+		}
+
+		if !hasNestedOperations(rule) {
+			lines = append(lines, coverageLine{Indent: indent, Text: prefix + compactJSON(rule) + suffix, Node: node, Branch: branch, Taken: taken})
+			return
+		}
+
+		list, isList := rule.([]any)
+		open, close := "[", "]"
+		if node != nil {
+			operator := node.Operator
+			args, ok := m[operator].([]any)
+			if !ok {
+				lines = append(lines, coverageLine{Indent: indent, Text: prefix + "{" + compactJSON(operator) + ":", Node: node, Branch: branch, Taken: taken})
+				layout(m[operator], depth+1, "", "", false, 0)
+				lines = append(lines, coverageLine{Indent: indent, Text: "}" + suffix})
+				return
+			}
+			list, isList = args, true
+			open, close = "{"+compactJSON(operator)+": [", "]}"
+		}
+
+		if !isList {
+			lines = append(lines, coverageLine{Indent: indent, Text: prefix + compactJSON(rule) + suffix, Node: node, Branch: branch, Taken: taken})
+			return
+		}
+
+		lines = append(lines, coverageLine{Indent: indent, Text: prefix + open, Node: node, Branch: branch, Taken: taken})
+		for i, item := range list {
+			separator := ","
+			if i == len(list)-1 {
+				separator = ""
+			}
+
+			isBranch, count := false, 0
+			if node != nil && node.Branches != nil && (i%2 == 1 || i == len(list)-1) {
+				isBranch, count = true, node.Branches[i/2]
+			}
+
+			layout(item, depth+1, "", separator, isBranch, count)
+		}
+		lines = append(lines, coverageLine{Indent: indent, Text: close + suffix})
+	}
+
+	layout(c.rule, 0, "", "", false, 0)
+
+	return lines
+}
+
+// hasNestedOperations reports whether the value contains operations other
+// than variables.
+func hasNestedOperations(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		if len(v) != 1 {
+			return false
+		}
+		for operator, args := range v {
+			if operator == "var" || operator == "val" {
+				return false
+			}
+			return containsOperations(args)
+		}
+	case []any:
+		return containsOperations(v)
+	}
+
+	return false
+}
+
+func containsOperations(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		return len(v) == 1 && (v["var"] == nil && v["val"] == nil || hasNestedOperations(v))
+	case []any:
+		for _, item := range v {
+			if containsOperations(item) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// compactJSON encodes the value on a single line, without escaping < and >.
+func compactJSON(value any) string {
+	var b bytes.Buffer
+
+	encoder := json.NewEncoder(&b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// WriteText writes the rule with the coverage of every operation and branch
+// at the start of its line:
+//
+//	3 evaluations: 2/2 operations evaluated, 2/2 branches taken
+//	3x, true 3, false 0 | {"if": [
+//	3x, true 2, false 1 |   {">=":[{"var":"age"},18]},
+//	taken 2x            |   "adult",
+//	taken 1x            |   "minor"
+//	                    | ]}
+func (c *Coverage) WriteText(w io.Writer) error {
+	report := c.Report()
+	lines := c.lines(report)
+
+	width := 0
+	for _, line := range lines {
+		if n := len(line.Annotation()); n > width {
+			width = n
+		}
+	}
+
+	if _, err := fmt.Fprintln(w, report); err != nil {
+		return err
+	}
+
+	for _, line := range lines {
+		if _, err := fmt.Fprintf(w, "%-*s | %s%s\n", width, line.Annotation(), line.Indent, line.Text); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var coverageHTML = template.Must(template.New("coverage").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>JsonLogic coverage</title>
+<style>
+body { font-family: sans-serif; }
+pre { line-height: 1.4; }
+.covered { background: #dfd; }
+.partial { background: #ffd; }
+.uncovered { background: #fdd; }
+.annotation { color: #666; display: inline-block; min-width: 24em; }
+</style>
+</head>
+<body>
+<p>{{.Report}}</p>
+<pre>
+{{range .Lines}}<span class="annotation">{{.Annotation}}</span><span class="{{.Class}}">{{.Indent}}{{.Text}}</span>
+{{end}}</pre>
+</body>
+</html>
+`))
+
+// WriteHTML writes a page showing the rule with the coverage of every
+// operation and branch, highlighting the operations never evaluated, the
+// branches never taken and the conditions that were never true or never
+// false.
+func (c *Coverage) WriteHTML(w io.Writer) error {
+	report := c.Report()
+
+	return coverageHTML.Execute(w, struct {
+		Report CoverageReport
+		Lines  []coverageLine
+	}{
+		Report: report,
+		Lines:  c.lines(report),
+	})
+}
@@ -0,0 +1,52 @@
+package jsonlogic_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func coverageOf(t *testing.T) *jsonlogic.Coverage {
+	rule := parseRule(t, `{"if": [
+		{"and": [{">=":[{"var":"age"},18]}, {"==":[{"var":"country"},"BR"]}]}, "allowed",
+		"denied"
+	]}`)
+
+	coverage := jsonlogic.NewCoverage(rule, jsonlogic.Options{})
+	_, err := coverage.ApplyInterface(map[string]any{"age": float64(15)})
+	assert.NoError(t, err)
+	_, err = coverage.ApplyInterface(map[string]any{"age": float64(25)})
+	assert.NoError(t, err)
+
+	return coverage
+}
+
+func TestCoverageWriteText(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, coverageOf(t).WriteText(&b))
+
+	assert.Equal(t, `2 evaluations: 6/6 operations evaluated, 1/2 branches taken
+2x, true 2, false 0            | {"if": [
+2x, true 0, false 2            |   {"and": [
+2x, true 1, false 1            |     {">=":[{"var":"age"},18]},
+1x, true 0, false 1, skipped 1 |     {"==":[{"var":"country"},"BR"]}
+                               |   ]},
+never taken                    |   "allowed",
+taken 2x                       |   "denied"
+                               | ]}
+`, b.String())
+}
+
+func TestCoverageWriteHTML(t *testing.T) {
+	var b bytes.Buffer
+	assert.NoError(t, coverageOf(t).WriteHTML(&b))
+
+	html := b.String()
+	assert.Contains(t, html, `<p>2 evaluations: 6/6 operations evaluated, 1/2 branches taken</p>`)
+	assert.Contains(t, html, `<span class="annotation">never taken</span><span class="uncovered">  &#34;allowed&#34;,</span>`)
+	assert.Contains(t, html, `<span class="annotation">2x, true 1, false 1</span><span class="covered">    {&#34;&gt;=&#34;:[{&#34;var&#34;:&#34;age&#34;},18]},</span>`)
+	assert.Contains(t, html, `<span class="partial">  {&#34;and&#34;: [</span>`)
+}
@@ -0,0 +1,88 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestCoverage(t *testing.T) {
+	rule := parseRule(t, `{"if": [
+		{"and": [{">=": [{"var": "age"}, 18]}, {"==": [{"var": "country"}, "BR"]}]}, "allowed",
+		{"<": [{"var": "age"}, 13]}, "child",
+		"denied"
+	]}`)
+
+	coverage := jsonlogic.NewCoverage(rule, jsonlogic.Options{})
+
+	for _, data := range []any{
+		map[string]any{"age": float64(20), "country": "BR"},
+		map[string]any{"age": float64(20), "country": "PT"},
+		map[string]any{"age": float64(15), "country": "BR"},
+	} {
+		_, err := coverage.ApplyInterface(data)
+		assert.NoError(t, err)
+	}
+
+	report := coverage.Report()
+	assert.Equal(t, 3, report.Evaluations)
+
+	nodes := map[string]jsonlogic.NodeCoverage{}
+	for _, node := range report.Nodes {
+		nodes[node.Path.Pointer()] = node
+	}
+
+	assert.Equal(t, jsonlogic.NodeCoverage{Path: jsonlogic.Path{}, Operator: "if", Evaluated: 3, True: 3, Branches: []int{1, 0, 2}}, nodes[""])
+	assert.Equal(t, jsonlogic.NodeCoverage{Path: jsonlogic.Path{"if", "0"}, Operator: "and", Evaluated: 3, True: 1, False: 2}, nodes["/if/0"])
+	assert.Equal(t, jsonlogic.NodeCoverage{Path: jsonlogic.Path{"if", "0", "and", "1"}, Operator: "==", Evaluated: 2, ShortCircuited: 1, True: 1, False: 1}, nodes["/if/0/and/1"])
+	assert.Equal(t, jsonlogic.NodeCoverage{Path: jsonlogic.Path{"if", "2"}, Operator: "<", Evaluated: 2, ShortCircuited: 1, False: 2}, nodes["/if/2"])
+	assert.Equal(t, 3, nodes["/if/0/and/0/>=/0"].Evaluated)
+
+	assert.Equal(t, "3 evaluations: 8/8 operations evaluated, 2/3 branches taken", report.String())
+
+	encoded, err := json.Marshal(report)
+	assert.NoError(t, err)
+	assert.Contains(t, string(encoded), `{"path":[],"operator":"if","evaluated":3,"short_circuited":0,"true":3,"false":0,"branches":[1,0,2]}`)
+
+	coverage.Reset()
+	assert.Equal(t, "0 evaluations: 0/8 operations evaluated, 0/3 branches taken", coverage.Report().String())
+}
+
+func TestCoverageWithFailures(t *testing.T) {
+	rule := parseRule(t, `{"or": [{"var": "a"}, {"unknown_operator": []}]}`)
+	coverage := jsonlogic.NewCoverage(rule, jsonlogic.Options{})
+
+	_, err := coverage.ApplyInterface(map[string]any{"a": false})
+	assert.Error(t, err)
+
+	report := coverage.Report()
+	assert.Equal(t, 1, report.Nodes[0].Evaluated)
+	assert.Equal(t, 1, report.Nodes[2].Evaluated)
+	assert.Equal(t, 0, report.Nodes[2].True+report.Nodes[2].False)
+}
+
+func TestCoverageIsSafeForConcurrentUse(t *testing.T) {
+	rule := parseRule(t, `{"filter": [{"var": "items"}, {">": [{"var": ""}, 2]}]}`)
+	coverage := jsonlogic.NewCoverage(rule, jsonlogic.Options{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output, err := coverage.ApplyRaw(json.RawMessage(`{"items": [1, 2, 3, 4]}`))
+			assert.NoError(t, err)
+			assert.JSONEq(t, `[3, 4]`, string(output))
+		}()
+	}
+	wg.Wait()
+
+	report := coverage.Report()
+	assert.Equal(t, 10, report.Evaluations)
+	assert.Equal(t, 40, report.Nodes[2].Evaluated)
+	assert.Equal(t, 20, report.Nodes[2].True)
+}
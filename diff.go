@@ -0,0 +1,314 @@
+package jsonlogic
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ChangeKind tells how a part of a rule changed between two versions.
+type ChangeKind int
+
+const (
+	ChangeAdded ChangeKind = iota
+	ChangeRemoved
+	ChangeModified
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case ChangeAdded:
+		return "added"
+	case ChangeRemoved:
+		return "removed"
+	case ChangeModified:
+		return "changed"
+	}
+
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a difference between two versions of a rule. Path locates the
+// part of the rule in the new version, or in the old one when it was removed.
+// Old is nil for additions and New is nil for removals.
+type Change struct {
+	Kind ChangeKind
+	Path Path
+	Old  any
+	New  any
+}
+
+func (c Change) String() string {
+	location := c.Path.Pointer()
+	if location == "" {
+		location = "(root)"
+	}
+
+	switch c.Kind {
+	case ChangeAdded:
+		return fmt.Sprintf("%s %s: %s", c.Kind, location, diffJSON(c.New))
+	case ChangeRemoved:
+		return fmt.Sprintf("%s %s: %s", c.Kind, location, diffJSON(c.Old))
+	}
+
+	return fmt.Sprintf("%s %s: %s -> %s", c.Kind, location, diffJSON(c.Old), diffJSON(c.New))
+}
+
+// commutative are the operators whose operands can be reordered without
+// changing the result.
+var commutative = map[string]bool{
+	"and": true,
+	"or":  true,
+}
+
+// Diff compares two versions of a rule structurally and returns the
+// conditions added, removed and changed, in the order they appear.
+//
+// Object keys are compared regardless of their order, the operands of "and"
+// and "or" regardless of their position, and an operation with a single
+// argument is the same as the operation with the argument in an array.
+// Variables are compared as a whole rather than by their path.
+//
+// Parameters:
+//   - old: interface{} representing the previous version of the rule
+//   - new: interface{} representing the current version of the rule
+//
+// Returns:
+//   - changes: the differences between the versions, empty when they are equivalent
+func Diff(old, new any) []Change {
+	var changes []Change
+	diffRules(Path{}, Path{}, old, new, &changes)
+	return changes
+}
+
+func diffRules(oldPath, newPath Path, old, new any, changes *[]Change) {
+	if diffKey(old) == diffKey(new) {
+		return
+	}
+
+	oldOp, oldArgs, oldList, isOldOp := diffOperation(old)
+	newOp, newArgs, newList, isNewOp := diffOperation(new)
+
+	if !isOldOp || !isNewOp || oldOp != newOp || oldOp == "var" || oldOp == "val" {
+		*changes = append(*changes, Change{Kind: ChangeModified, Path: newPath, Old: old, New: new})
+		return
+	}
+
+	argPath := func(path Path, list bool, i int) Path {
+		path = append(path[:len(path):len(path)], oldOp)
+		if !list {
+			return path
+		}
+		return append(path, strconv.Itoa(i))
+	}
+
+	if commutative[oldOp] {
+		diffOperands(oldArgs, newArgs, func(i int) Path { return argPath(oldPath, oldList, i) }, func(i int) Path { return argPath(newPath, newList, i) }, changes)
+		return
+	}
+
+	for i := 0; i < len(oldArgs) || i < len(newArgs); i++ {
+		switch {
+		case i >= len(newArgs):
+			*changes = append(*changes, Change{Kind: ChangeRemoved, Path: argPath(oldPath, oldList, i), Old: oldArgs[i]})
+		case i >= len(oldArgs):
+			*changes = append(*changes, Change{Kind: ChangeAdded, Path: argPath(newPath, newList, i), New: newArgs[i]})
+		default:
+			diffRules(argPath(oldPath, oldList, i), argPath(newPath, newList, i), oldArgs[i], newArgs[i], changes)
+		}
+	}
+}
+
+// diffOperands compares the operands of a commutative operation. Equal
+// operands match wherever they are, and the remaining ones are compared
+// with the first remaining operand of the same operator, if any.
+func diffOperands(old, new []any, oldPath, newPath func(int) Path, changes *[]Change) {
+	oldMatched := make([]bool, len(old))
+	newMatched := make([]bool, len(new))
+
+	for j := range new {
+		for i := range old {
+			if !oldMatched[i] && diffKey(old[i]) == diffKey(new[j]) {
+				oldMatched[i], newMatched[j] = true, true
+				break
+			}
+		}
+	}
+
+	for j := range new {
+		if newMatched[j] {
+			continue
+		}
+
+		newOp, _, _, _ := diffOperation(new[j])
+
+		paired := false
+		for i := range old {
+			if oldOp, _, _, ok := diffOperation(old[i]); oldMatched[i] || !ok || oldOp != newOp {
+				continue
+			}
+
+			oldMatched[i], paired = true, true
+			diffRules(oldPath(i), newPath(j), old[i], new[j], changes)
+			break
+		}
+
+		if !paired {
+			*changes = append(*changes, Change{Kind: ChangeAdded, Path: newPath(j), New: new[j]})
+		}
+	}
+
+	for i := range old {
+		if !oldMatched[i] {
+			*changes = append(*changes, Change{Kind: ChangeRemoved, Path: oldPath(i), Old: old[i]})
+		}
+	}
+}
+
+// diffOperation returns the operator and the arguments of an operation, and
+// whether the arguments are written as an array.
+func diffOperation(rule any) (string, []any, bool, bool) {
+	m, ok := rule.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", nil, false, false
+	}
+
+	for operator, values := range m {
+		if args, ok := values.([]any); ok {
+			return operator, args, true, true
+		}
+		return operator, []any{values}, false, true
+	}
+
+	return "", nil, false, false
+}
+
+// diffKey returns the same key for the rules Diff considers equal.
+func diffKey(rule any) string {
+	key, err := canonicalKey(diffNormalize(rule))
+	if err != nil {
+		return fmt.Sprintf("%#v", rule)
+	}
+	return key
+}
+
+// diffNormalize writes the arguments of the operations as arrays and sorts
+// the operands of the commutative operations.
+func diffNormalize(rule any) any {
+	switch value := rule.(type) {
+	case []any:
+		normalized := make([]any, len(value))
+		for i, item := range value {
+			normalized[i] = diffNormalize(item)
+		}
+		return normalized
+	case map[string]any:
+		operator, args, _, ok := diffOperation(value)
+		if !ok {
+			return value
+		}
+
+		normalized := diffNormalize(args).([]any)
+		if commutative[operator] {
+			keys := make([]string, len(normalized))
+			for i, arg := range normalized {
+				keys[i], _ = canonicalKey(arg)
+			}
+			sort.Sort(byKey{keys: keys, values: normalized})
+		}
+
+		return map[string]any{operator: normalized}
+	}
+
+	return rule
+}
+
+type byKey struct {
+	keys   []string
+	values []any
+}
+
+func (b byKey) Len() int           { return len(b.keys) }
+func (b byKey) Less(i, j int) bool { return b.keys[i] < b.keys[j] }
+func (b byKey) Swap(i, j int) {
+	b.keys[i], b.keys[j] = b.keys[j], b.keys[i]
+	b.values[i], b.values[j] = b.values[j], b.values[i]
+}
+
+// diffJSON encodes the value for people to read, without escaping < and >.
+func diffJSON(value any) string {
+	var s strings.Builder
+
+	encoder := json.NewEncoder(&s)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Sprintf("%#v", value)
+	}
+
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// OutcomeChange is a record on which two versions of a rule disagree. Index
+// is the position of the record in the sample.
+type OutcomeChange struct {
+	Index  int
+	Record any
+	Old    any
+	New    any
+	OldErr error
+	NewErr error
+}
+
+func (c OutcomeChange) String() string {
+	outcome := func(result any, err error) string {
+		if err != nil {
+			return "error: " + err.Error()
+		}
+		return diffJSON(result)
+	}
+
+	return fmt.Sprintf("record %d %s: %s -> %s", c.Index, diffJSON(c.Record), outcome(c.Old, c.OldErr), outcome(c.New, c.NewErr))
+}
+
+// DiffOutcomes applies two versions of a rule to every record of a sample and
+// returns the records for which they produce different results, or for which
+// only one of them fails.
+//
+// Parameters:
+//   - old: interface{} representing the previous version of the rule
+//   - new: interface{} representing the current version of the rule
+//   - records: the sample of data to apply both versions to
+//
+// Returns:
+//   - changes: the records whose outcome changed, in the order of the sample
+func DiffOutcomes(old, new any, records []any) []OutcomeChange {
+	var changes []OutcomeChange
+
+	for i, record := range records {
+		oldResult, oldErr := ApplyInterface(old, record)
+		newResult, newErr := ApplyInterface(new, record)
+
+		switch {
+		case oldErr != nil || newErr != nil:
+			if oldErr != nil && newErr != nil && oldErr.Error() == newErr.Error() {
+				continue
+			}
+		case reflect.DeepEqual(oldResult, newResult):
+			continue
+		}
+
+		changes = append(changes, OutcomeChange{
+			Index:  i,
+			Record: record,
+			Old:    oldResult,
+			New:    newResult,
+			OldErr: oldErr,
+			NewErr: newErr,
+		})
+	}
+
+	return changes
+}
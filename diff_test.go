@@ -0,0 +1,141 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func parseRule(t *testing.T, rule string) any {
+	t.Helper()
+
+	var parsed any
+	if err := json.Unmarshal([]byte(rule), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func diffStrings(changes []jsonlogic.Change) []string {
+	result := make([]string, 0, len(changes))
+	for _, change := range changes {
+		result = append(result, change.String())
+	}
+	return result
+}
+
+func TestDiff(t *testing.T) {
+	scenarios := map[string]struct {
+		old      string
+		new      string
+		expected []string
+	}{
+		"equal rules": {
+			old:      `{"and": [{"<": [{"var": "age"}, 18]}, {"==": [{"var": "country"}, "BR"]}]}`,
+			new:      `{"and": [{"<": [{"var": "age"}, 18]}, {"==": [{"var": "country"}, "BR"]}]}`,
+			expected: []string{},
+		},
+		"reordered operands": {
+			old:      `{"or": [{"var": "vip"}, {"and": [true, {"var": "beta"}]}]}`,
+			new:      `{"or": [{"and": [{"var": "beta"}, true]}, {"var": "vip"}]}`,
+			expected: []string{},
+		},
+		"single argument written without an array": {
+			old:      `{"!": [{"var": ["vip"]}]}`,
+			new:      `{"!": {"var": "vip"}}`,
+			expected: []string{},
+		},
+		"changed literal": {
+			old:      `{"and": [{"<": [{"var": "age"}, 18]}, {"var": "active"}]}`,
+			new:      `{"and": [{"var": "active"}, {"<": [{"var": "age"}, 21]}]}`,
+			expected: []string{`changed /and/1/</1: 18 -> 21`},
+		},
+		"changed variable": {
+			old:      `{"<": [{"var": "age"}, 18]}`,
+			new:      `{"<": [{"var": "years"}, 18]}`,
+			expected: []string{`changed /</0: {"var":"age"} -> {"var":"years"}`},
+		},
+		"added and removed conditions": {
+			old:      `{"and": [{"var": "active"}, {"==": [{"var": "country"}, "BR"]}]}`,
+			new:      `{"and": [{"var": "active"}, {"in": [{"var": "plan"}, ["pro", "team"]]}]}`,
+			expected: []string{`added /and/1: {"in":[{"var":"plan"},["pro","team"]]}`, `removed /and/1: {"==":[{"var":"country"},"BR"]}`},
+		},
+		"added argument": {
+			old:      `{"cat": ["a", "b"]}`,
+			new:      `{"cat": ["a", "b", "c"]}`,
+			expected: []string{`added /cat/2: "c"`},
+		},
+		"changed operator": {
+			old:      `{"<": [{"var": "age"}, 18]}`,
+			new:      `{"<=": [{"var": "age"}, 18]}`,
+			expected: []string{`changed (root): {"<":[{"var":"age"},18]} -> {"<=":[{"var":"age"},18]}`},
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			changes := jsonlogic.Diff(parseRule(t, scenario.old), parseRule(t, scenario.new))
+			assert.Equal(t, scenario.expected, diffStrings(changes))
+		})
+	}
+}
+
+func TestDiffChanges(t *testing.T) {
+	changes := jsonlogic.Diff(
+		parseRule(t, `{"if": [{"var": "vip"}, 10, 0]}`),
+		parseRule(t, `{"if": [{"var": "vip"}, 15]}`),
+	)
+
+	assert.Equal(t, []jsonlogic.Change{
+		{Kind: jsonlogic.ChangeModified, Path: jsonlogic.Path{"if", "1"}, Old: float64(10), New: float64(15)},
+		{Kind: jsonlogic.ChangeRemoved, Path: jsonlogic.Path{"if", "2"}, Old: float64(0)},
+	}, changes)
+}
+
+func TestDiffOutcomes(t *testing.T) {
+	records := []any{
+		map[string]any{"age": float64(17)},
+		map[string]any{"age": float64(19)},
+		map[string]any{"age": float64(25)},
+		map[string]any{"age": "unknown"},
+	}
+
+	changes := jsonlogic.DiffOutcomes(
+		parseRule(t, `{">=": [{"var": "age"}, 18]}`),
+		parseRule(t, `{">=": [{"var": "age"}, 21]}`),
+		records,
+	)
+
+	assert.Len(t, changes, 1)
+	assert.Equal(t, 1, changes[0].Index)
+	assert.Equal(t, true, changes[0].Old)
+	assert.Equal(t, false, changes[0].New)
+	assert.Equal(t, `record 1 {"age":19}: true -> false`, changes[0].String())
+
+	changes = jsonlogic.DiffOutcomes(
+		parseRule(t, `{"+": [{"var": "n"}, 1]}`),
+		parseRule(t, `{"unknown_operator": [{"var": "n"}]}`),
+		[]any{map[string]any{"n": float64(1)}},
+	)
+
+	assert.Len(t, changes, 1)
+	assert.NoError(t, changes[0].OldErr)
+	assert.Error(t, changes[0].NewErr)
+
+	changes = jsonlogic.DiffOutcomes(
+		parseRule(t, `{"var": "old"}`),
+		parseRule(t, `{"var": "new"}`),
+		[]any{
+			parseRule(t, `{"old": {"x": 5}, "new": {"x": [5]}}`),
+			parseRule(t, `{"old": {"and": [1, 2]}, "new": {"and": [2, 1]}}`),
+			parseRule(t, `{"old": {"x": 5}, "new": {"x": 5}}`),
+		},
+	)
+
+	assert.Len(t, changes, 2)
+	assert.Equal(t, `record 0 {"new":{"x":[5]},"old":{"x":5}}: {"x":5} -> {"x":[5]}`, changes[0].String())
+	assert.Equal(t, 1, changes[1].Index)
+}
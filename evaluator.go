@@ -0,0 +1,141 @@
+package jsonlogic
+
+import (
+	"reflect"
+	"strconv"
+)
+
+// evaluator holds the state of a single evaluation. Every operator receives
+// it so the state can follow the evaluation into the nested rules.
+type evaluator struct {
+	// scopes holds the data of the document and of every iteration being
+	// evaluated, from the outermost to the innermost.
+	scopes []scope
+
+	// bindings holds the names bound by the "let" operations being evaluated,
+	// from the outermost to the innermost.
+	bindings []map[string]any
+
+	// strict disables the implicit conversions of comparisons and arithmetic
+	// and makes reading absent variables fail.
+	strict bool
+
+	// profile selects the behavior of the edge cases.
+	profile Profile
+
+	// root is the rule being evaluated and node the operation being applied,
+	// used to locate the operations that fail.
+	root any
+	node map[string]any
+
+	// shared maps the subexpressions to memoize to their canonical form and
+	// memo holds their results. Both are nil unless memoization is enabled.
+	shared map[uintptr]string
+	memo   map[string]any
+
+	// coverage records the operations evaluated, when measuring coverage.
+	coverage *coverageRun
+}
+
+// scope is the data an expression is evaluated against. The root scope holds
+// the document and each iteration adds a scope holding the current element.
+type scope struct {
+	data  any
+	index int
+	array []any
+
+	// outerFirst is set by filter, all, none and some, whose variables are
+	// looked up in the enclosing data before the element.
+	outerFirst bool
+}
+
+func newEvaluator() *evaluator {
+	return &evaluator{}
+}
+
+func newEvaluatorWithOptions(opts Options) *evaluator {
+	return &evaluator{strict: opts.Strict, profile: opts.Profile}
+}
+
+// enterScope must be called before iterating over the array, and the returned
+// function once the iteration is done. Each element must be set with
+// setElement before being evaluated.
+func (e *evaluator) enterScope(array []any, outerFirst bool) func() {
+	e.scopes = append(e.scopes, scope{array: array, outerFirst: outerFirst})
+	return func() { e.scopes = e.scopes[:len(e.scopes)-1] }
+}
+
+// setElement updates the innermost scope with the element being evaluated.
+func (e *evaluator) setElement(index int, data any) {
+	s := &e.scopes[len(e.scopes)-1]
+	s.index = index
+	s.data = data
+}
+
+// iterating reports whether an iteration body is being evaluated, in which
+// case the data is an element of an array rather than the document.
+func (e *evaluator) iterating() bool {
+	return len(e.scopes) > 1
+}
+
+// bind makes the names visible to the variables until the returned function
+// is called.
+func (e *evaluator) bind(names map[string]any) func() {
+	e.bindings = append(e.bindings, names)
+	return func() { e.bindings = e.bindings[:len(e.bindings)-1] }
+}
+
+// binding returns the value bound to the name by the innermost "let" binding it.
+func (e *evaluator) binding(name string) (any, bool) {
+	for i := len(e.bindings) - 1; i >= 0; i-- {
+		if value, ok := e.bindings[i][name]; ok {
+			return value, true
+		}
+	}
+
+	return nil, false
+}
+
+// locate returns the path from the root of the rule to the operation.
+func (e *evaluator) locate(node map[string]any) (Path, bool) {
+	if node == nil {
+		return nil, false
+	}
+
+	target := reflect.ValueOf(node).Pointer()
+
+	var find func(rule any, path Path) (Path, bool)
+	find = func(rule any, path Path) (Path, bool) {
+		switch value := rule.(type) {
+		case map[string]any:
+			if reflect.ValueOf(value).Pointer() == target {
+				return path, true
+			}
+			for key, child := range value {
+				if found, ok := find(child, append(path[:len(path):len(path)], key)); ok {
+					return found, true
+				}
+			}
+		case []any:
+			for i, child := range value {
+				if found, ok := find(child, append(path[:len(path):len(path)], strconv.Itoa(i))); ok {
+					return found, true
+				}
+			}
+		}
+
+		return nil, false
+	}
+
+	return find(e.root, Path{})
+}
+
+// memoized returns the key used to memoize the result of the rule, if any.
+func (e *evaluator) memoized(rule map[string]any) (string, bool) {
+	if e.shared == nil || e.iterating() || len(e.bindings) > 0 {
+		return "", false
+	}
+
+	key, ok := e.shared[reflect.ValueOf(rule).Pointer()]
+	return key, ok
+}
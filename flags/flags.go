@@ -0,0 +1,292 @@
+// Package flags evaluates feature flags whose targeting rules are written in JSON Logic.
+//
+// A flag has a set of named variants, an ordered list of targeting rules, a
+// fallthrough served when no rule matches and a default variant served when
+// nothing else applies. Rules and the fallthrough serve either a fixed variant
+// or a rollout that splits the users between variants by weight.
+//
+// # Bucketing
+//
+// Rollouts are deterministic: a user always lands in the same bucket for the
+// same salt. The bucket is computed as follows, so it can be reproduced in
+// any language:
+//
+//  1. The bucketing value is read from the data with the flag's BucketBy path,
+//     or "id" when it's empty, and turned into a string: strings are used
+//     as-is, numbers use the shortest decimal representation without
+//     exponent (42, 0.5, -3), and booleans become "true" or "false". Other
+//     values can't be bucketed.
+//  2. The salt is the flag's Salt, or its Key when Salt is empty.
+//  3. The input "<salt>.<value>" is hashed with SHA-256.
+//  4. The first 4 bytes of the digest are read as a big-endian unsigned
+//     32-bit integer and the bucket is that integer modulo 10000.
+//
+// For example, the bucket of "user-1" for a flag with the key "new-checkout"
+// and no salt is the first 4 bytes of sha256("new-checkout.user-1") modulo
+// 10000.
+//
+// Weights are expressed in the same unit as buckets, so a weight of 2500
+// means 25%. This algorithm is part of the package's compatibility promise
+// and won't change across releases.
+package flags
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/internal/javascript"
+)
+
+// BucketCount is the number of buckets users are distributed across.
+const BucketCount = 10000
+
+// DefaultBucketBy is the variable used for bucketing when a flag doesn't set one.
+const DefaultBucketBy = "id"
+
+// Reason explains why a variant was selected.
+type Reason string
+
+const (
+	// ReasonTargetingMatch means a targeting rule matched.
+	ReasonTargetingMatch Reason = "TARGETING_MATCH"
+	// ReasonFallthrough means no targeting rule matched and the fallthrough was served.
+	ReasonFallthrough Reason = "FALLTHROUGH"
+	// ReasonDefault means neither a rule nor the fallthrough served a variant.
+	ReasonDefault Reason = "DEFAULT"
+	// ReasonError means the flag couldn't be evaluated and the default variant was served.
+	ReasonError Reason = "ERROR"
+)
+
+type (
+	// Flag is the definition of a feature flag.
+	Flag struct {
+		// Key identifies the flag.
+		Key string `json:"key"`
+		// Salt is hashed with the bucketing value to pick the bucket of a
+		// user. It defaults to Key when empty; changing it reshuffles the
+		// users between the variants of the rollouts.
+		Salt string `json:"salt,omitempty"`
+		// BucketBy is the path of the variable bucketed by the rollouts,
+		// DefaultBucketBy when empty.
+		BucketBy string `json:"bucketBy,omitempty"`
+		// Variants are the values the flag can serve, by name.
+		Variants map[string]any `json:"variants"`
+		// Rules are the targeting rules, tried in order.
+		Rules []Rule `json:"rules,omitempty"`
+		// Fallthrough is served when no rule serves a variant, if set.
+		Fallthrough *Serve `json:"fallthrough,omitempty"`
+		// Default is the variant served when neither the rules nor the
+		// fallthrough serve one, or when the evaluation fails.
+		Default string `json:"default"`
+	}
+
+	// Rule serves a variant to the users for which its JSON Logic condition is truthy.
+	Rule struct {
+		// ID identifies the rule in the results, optionally.
+		ID string `json:"id,omitempty"`
+		// If is the JSON Logic condition, evaluated against the data.
+		If any `json:"if"`
+		// Serve is what the rule serves when its condition is truthy.
+		Serve Serve `json:"serve"`
+	}
+
+	// Serve describes which variant is served: either a fixed Variant or a
+	// Rollout. When the weights of a rollout add up to less than BucketCount,
+	// users in the remaining buckets aren't served by it and the evaluation
+	// moves on to the next rule.
+	Serve struct {
+		// Variant is the name of the variant served to every user.
+		Variant string `json:"variant,omitempty"`
+		// Rollout splits the users between variants, when Variant is empty.
+		Rollout []WeightedVariant `json:"rollout,omitempty"`
+	}
+
+	// WeightedVariant is a variant served to Weight out of BucketCount buckets.
+	WeightedVariant struct {
+		Variant string `json:"variant"`
+		Weight  int    `json:"weight"`
+	}
+
+	// Result is the outcome of a flag evaluation.
+	Result struct {
+		Variant   string `json:"variant"`
+		Value     any    `json:"value"`
+		Reason    Reason `json:"reason"`
+		RuleIndex int    `json:"ruleIndex"`
+		RuleID    string `json:"ruleId,omitempty"`
+	}
+)
+
+// ErrUnknownVariant is returned when a flag references a variant it doesn't define.
+var ErrUnknownVariant = errors.New("flags: unknown variant")
+
+// Evaluate selects the variant of the flag for the given data.
+//
+// Rules are tried in order and the first truthy one that serves a variant wins.
+// When an error happens the default variant is returned along with the error
+// and ReasonError. RuleIndex is -1 unless the reason is ReasonTargetingMatch.
+func Evaluate(flag Flag, data any) (Result, error) {
+	if err := flag.Validate(); err != nil {
+		return flag.result(flag.Default, ReasonError, -1), err
+	}
+
+	for i, rule := range flag.Rules {
+		matched, err := jsonlogic.ApplyInterface(rule.If, data)
+		if err != nil {
+			return flag.result(flag.Default, ReasonError, -1), fmt.Errorf("flags: rule %d of %q: %w", i, flag.Key, err)
+		}
+
+		if !javascript.IsTrue(matched) {
+			continue
+		}
+
+		if variant, ok := flag.serve(rule.Serve, data); ok {
+			result := flag.result(variant, ReasonTargetingMatch, i)
+			result.RuleID = rule.ID
+			return result, nil
+		}
+	}
+
+	if flag.Fallthrough != nil {
+		if variant, ok := flag.serve(*flag.Fallthrough, data); ok {
+			return flag.result(variant, ReasonFallthrough, -1), nil
+		}
+	}
+
+	return flag.result(flag.Default, ReasonDefault, -1), nil
+}
+
+// Validate checks that every variant referenced by the flag is defined and
+// that no rollout has weights adding up to more than BucketCount.
+func (f Flag) Validate() error {
+	if _, ok := f.Variants[f.Default]; !ok {
+		return fmt.Errorf("%w %q used as default of %q", ErrUnknownVariant, f.Default, f.Key)
+	}
+
+	serves := make([]Serve, 0, len(f.Rules)+1)
+	for _, rule := range f.Rules {
+		serves = append(serves, rule.Serve)
+	}
+	if f.Fallthrough != nil {
+		serves = append(serves, *f.Fallthrough)
+	}
+
+	for _, serve := range serves {
+		if serve.Variant != "" {
+			if _, ok := f.Variants[serve.Variant]; !ok {
+				return fmt.Errorf("%w %q served by %q", ErrUnknownVariant, serve.Variant, f.Key)
+			}
+		}
+
+		total := 0
+		for _, weighted := range serve.Rollout {
+			if _, ok := f.Variants[weighted.Variant]; !ok {
+				return fmt.Errorf("%w %q rolled out by %q", ErrUnknownVariant, weighted.Variant, f.Key)
+			}
+			if weighted.Weight < 0 {
+				return fmt.Errorf("flags: negative weight for variant %q of %q", weighted.Variant, f.Key)
+			}
+			total += weighted.Weight
+		}
+
+		if total > BucketCount {
+			return fmt.Errorf("flags: rollout weights of %q add up to %d, more than %d", f.Key, total, BucketCount)
+		}
+	}
+
+	return nil
+}
+
+func (f Flag) serve(serve Serve, data any) (string, bool) {
+	if serve.Variant != "" {
+		return serve.Variant, true
+	}
+
+	if len(serve.Rollout) == 0 {
+		return "", false
+	}
+
+	bucketBy := f.BucketBy
+	if bucketBy == "" {
+		bucketBy = DefaultBucketBy
+	}
+
+	value, err := jsonlogic.ApplyInterface(map[string]any{"var": bucketBy}, data)
+	if err != nil {
+		return "", false
+	}
+
+	salt := f.Salt
+	if salt == "" {
+		salt = f.Key
+	}
+
+	bucket, ok := Bucket(salt, value)
+	if !ok {
+		return "", false
+	}
+
+	return Variant(serve.Rollout, bucket)
+}
+
+func (f Flag) result(variant string, reason Reason, ruleIndex int) Result {
+	return Result{
+		Variant:   variant,
+		Value:     f.Variants[variant],
+		Reason:    reason,
+		RuleIndex: ruleIndex,
+	}
+}
+
+// Bucket returns the bucket, between 0 and BucketCount-1, of the value for the
+// given salt. It returns false when the value is not a string, number or boolean.
+// See the package documentation for the exact algorithm.
+func Bucket(salt string, value any) (int, bool) {
+	var s string
+
+	switch v := value.(type) {
+	case string:
+		s = v
+	case float64:
+		s = strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		s = strconv.FormatBool(v)
+	default:
+		return 0, false
+	}
+
+	digest := sha256.Sum256([]byte(salt + "." + s))
+
+	return int(binary.BigEndian.Uint32(digest[:4]) % BucketCount), true
+}
+
+// Variant returns the variant of the rollout that owns the bucket. Buckets are
+// assigned to the variants in the order they are listed, so it returns false
+// when the bucket falls beyond the sum of the weights.
+func Variant(rollout []WeightedVariant, bucket int) (string, bool) {
+	upper := 0
+
+	for _, weighted := range rollout {
+		upper += weighted.Weight
+		if bucket < upper {
+			return weighted.Variant, true
+		}
+	}
+
+	return "", false
+}
+
+// InRollout reports whether the value falls within the first percentage of
+// buckets for the given salt. The percentage goes from 0 to 100.
+func InRollout(salt string, value any, percentage float64) bool {
+	bucket, ok := Bucket(salt, value)
+	if !ok {
+		return false
+	}
+
+	return float64(bucket) < percentage*BucketCount/100
+}
@@ -0,0 +1,152 @@
+package flags_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/diegoholiveira/jsonlogic/v3/flags"
+)
+
+func TestBucketIsStable(t *testing.T) {
+	// these values are part of the compatibility promise and must never change
+	scenarios := []struct {
+		salt     string
+		value    any
+		expected int
+	}{
+		{salt: "new-checkout", value: "user-1", expected: 7643},
+		{salt: "new-checkout", value: "user-2", expected: 4137},
+		{salt: "new-checkout", value: float64(42), expected: 3828},
+		{salt: "salt", value: true, expected: 679},
+	}
+
+	for _, scenario := range scenarios {
+		bucket, ok := flags.Bucket(scenario.salt, scenario.value)
+		assert.True(t, ok)
+		assert.Equal(t, scenario.expected, bucket, "bucket of %v with salt %q", scenario.value, scenario.salt)
+	}
+
+	_, ok := flags.Bucket("salt", nil)
+	assert.False(t, ok)
+
+	_, ok = flags.Bucket("salt", []any{"user-1"})
+	assert.False(t, ok)
+}
+
+func TestInRollout(t *testing.T) {
+	assert.True(t, flags.InRollout("new-checkout", "user-2", 50))
+	assert.False(t, flags.InRollout("new-checkout", "user-1", 50))
+	assert.True(t, flags.InRollout("new-checkout", "user-1", 100))
+	assert.False(t, flags.InRollout("new-checkout", "user-1", 0))
+	assert.False(t, flags.InRollout("new-checkout", nil, 100))
+}
+
+func TestVariant(t *testing.T) {
+	rollout := []flags.WeightedVariant{
+		{Variant: "a", Weight: 2500},
+		{Variant: "b", Weight: 2500},
+	}
+
+	variant, ok := flags.Variant(rollout, 0)
+	assert.True(t, ok)
+	assert.Equal(t, "a", variant)
+
+	variant, ok = flags.Variant(rollout, 2500)
+	assert.True(t, ok)
+	assert.Equal(t, "b", variant)
+
+	_, ok = flags.Variant(rollout, 5000)
+	assert.False(t, ok)
+}
+
+func TestEvaluate(t *testing.T) {
+	var flag flags.Flag
+	err := json.Unmarshal([]byte(`{
+		"key": "new-checkout",
+		"salt": "exp",
+		"bucketBy": "user.id",
+		"variants": {"on": true, "off": false, "beta": "beta"},
+		"rules": [
+			{"id": "staff", "if": {"==": [{"var": "user.role"}, "staff"]}, "serve": {"variant": "beta"}},
+			{"id": "brazil-half", "if": {"==": [{"var": "user.country"}, "BR"]}, "serve": {"rollout": [{"variant": "on", "weight": 5000}]}}
+		],
+		"fallthrough": {"rollout": [{"variant": "on", "weight": 1000}, {"variant": "off", "weight": 9000}]},
+		"default": "off"
+	}`), &flag)
+	assert.NoError(t, err)
+
+	scenarios := map[string]struct {
+		data     string
+		expected flags.Result
+	}{
+		"targeted by the first rule": {
+			data:     `{"user": {"id": "user-1", "role": "staff", "country": "BR"}}`,
+			expected: flags.Result{Variant: "beta", Value: "beta", Reason: flags.ReasonTargetingMatch, RuleIndex: 0, RuleID: "staff"},
+		},
+		"inside the percentage of a rule": {
+			data:     `{"user": {"id": "user-3", "country": "BR"}}`,
+			expected: flags.Result{Variant: "on", Value: true, Reason: flags.ReasonTargetingMatch, RuleIndex: 1, RuleID: "brazil-half"},
+		},
+		"outside the percentage of a rule falls through": {
+			data:     `{"user": {"id": "user-2", "country": "BR"}}`,
+			expected: flags.Result{Variant: "off", Value: false, Reason: flags.ReasonFallthrough, RuleIndex: -1},
+		},
+		"fallthrough rollout": {
+			data:     `{"user": {"id": "user-3", "country": "PT"}}`,
+			expected: flags.Result{Variant: "on", Value: true, Reason: flags.ReasonFallthrough, RuleIndex: -1},
+		},
+		"nothing to bucket by": {
+			data:     `{"user": {"country": "PT"}}`,
+			expected: flags.Result{Variant: "off", Value: false, Reason: flags.ReasonDefault, RuleIndex: -1},
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			var data any
+			assert.NoError(t, json.Unmarshal([]byte(scenario.data), &data))
+
+			result, err := flags.Evaluate(flag, data)
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, result)
+		})
+	}
+}
+
+func TestEvaluateWithInvalidFlag(t *testing.T) {
+	flag := flags.Flag{
+		Key:      "broken",
+		Variants: map[string]any{"off": false},
+		Rules: []flags.Rule{
+			{If: true, Serve: flags.Serve{Variant: "on"}},
+		},
+		Default: "off",
+	}
+
+	result, err := flags.Evaluate(flag, nil)
+	assert.ErrorIs(t, err, flags.ErrUnknownVariant)
+	assert.Equal(t, flags.Result{Variant: "off", Value: false, Reason: flags.ReasonError, RuleIndex: -1}, result)
+
+	flag.Rules = []flags.Rule{
+		{If: true, Serve: flags.Serve{Rollout: []flags.WeightedVariant{{Variant: "off", Weight: 10001}}}},
+	}
+	_, err = flags.Evaluate(flag, nil)
+	assert.Error(t, err)
+}
+
+func TestEvaluateWithFailingRule(t *testing.T) {
+	flag := flags.Flag{
+		Key:      "failing",
+		Variants: map[string]any{"off": false},
+		Rules: []flags.Rule{
+			{If: map[string]any{"unknown_operator": []any{}}, Serve: flags.Serve{Variant: "off"}},
+		},
+		Default: "off",
+	}
+
+	result, err := flags.Evaluate(flag, nil)
+	assert.Error(t, err)
+	assert.Equal(t, flags.ReasonError, result.Reason)
+}
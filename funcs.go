@@ -0,0 +1,294 @@
+package jsonlogic
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+
+	"github.com/diegoholiveira/jsonlogic/v3/internal/javascript"
+)
+
+// Signature describes the arguments and the result of an operator registered
+// with RegisterFunc1, RegisterFunc2 or RegisterFunc3. Types are named as in
+// JSON Schema: "number", "integer", "string", "boolean", "array", "object",
+// or "any" for values of any type.
+type Signature struct {
+	Args   []string
+	Result string
+}
+
+// ArgumentError represents an argument of a typed operator that can't be
+// converted to the type of the parameter. Index starts at 0.
+type ArgumentError struct {
+	Operator string
+	Index    int
+	Expected string
+	Value    any
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("The argument %d of \"%s\" must be of type \"%s\", got %#v", e.Index, e.Operator, e.Expected, e.Value)
+}
+
+// ArityError represents a typed operator used with the wrong number of arguments.
+type ArityError struct {
+	Operator string
+	Expected int
+	Got      int
+}
+
+func (e *ArityError) Error() string {
+	return fmt.Sprintf("The operator \"%s\" expects %d arguments, got %d", e.Operator, e.Expected, e.Got)
+}
+
+// signatures holds the signatures of the typed operators.
+var signatures = make(map[string]Signature)
+
+// LookupSignature returns the signature of an operator registered with
+// RegisterFunc1, RegisterFunc2 or RegisterFunc3.
+func LookupSignature(name string) (Signature, bool) {
+	operatorsLock.RLock()
+	defer operatorsLock.RUnlock()
+
+	signature, ok := signatures[name]
+	if !ok {
+		return Signature{}, false
+	}
+
+	return Signature{
+		Args:   append([]string(nil), signature.Args...),
+		Result: signature.Result,
+	}, true
+}
+
+// Signatures returns the signatures of every operator registered with
+// RegisterFunc1, RegisterFunc2 or RegisterFunc3, by name.
+func Signatures() map[string]Signature {
+	operatorsLock.RLock()
+	defer operatorsLock.RUnlock()
+
+	result := make(map[string]Signature, len(signatures))
+	for name, signature := range signatures {
+		result[name] = Signature{
+			Args:   append([]string(nil), signature.Args...),
+			Result: signature.Result,
+		}
+	}
+
+	return result
+}
+
+// RegisterFunc1 registers a custom operator taking one argument. The argument
+// is converted to the parameter type with the JavaScript-like rules used by
+// the built-in operators, and ValidateJsonLogic rejects the rules using the
+// operator with the wrong number of arguments or with literals that can't be
+// converted.
+//
+// The supported types are float64, int, string, bool, []any, map[string]any
+// and any. A number is converted to int only when it has no fractional part.
+// Any other type makes the registration panic.
+//
+// Parameters:
+//   - name: the operator name to register (e.g., "slugify")
+//   - fn: the function to execute when the operator is encountered
+//
+// The errors returned by fn, *ArgumentError and *ArityError are wrapped in an
+// OperatorError, like the ones of AddOperatorWithError.
+//
+// Concurrency: This function is safe for concurrent use as it properly locks the operators map.
+func RegisterFunc1[A, R any](name string, fn func(A) (R, error)) {
+	registerFunc(name, []funcType{typeOf[A]()}, typeOf[R](), func(args []any) (any, error) {
+		return fn(as[A](args[0]))
+	})
+}
+
+// RegisterFunc2 registers a custom operator taking two arguments, converted
+// like the argument of RegisterFunc1.
+//
+// Parameters:
+//   - name: the operator name to register (e.g., "round_to")
+//   - fn: the function to execute when the operator is encountered
+//
+// Concurrency: This function is safe for concurrent use as it properly locks the operators map.
+func RegisterFunc2[A, B, R any](name string, fn func(A, B) (R, error)) {
+	registerFunc(name, []funcType{typeOf[A](), typeOf[B]()}, typeOf[R](), func(args []any) (any, error) {
+		return fn(as[A](args[0]), as[B](args[1]))
+	})
+}
+
+// RegisterFunc3 registers a custom operator taking three arguments, converted
+// like the argument of RegisterFunc1.
+//
+// Parameters:
+//   - name: the operator name to register (e.g., "clamp")
+//   - fn: the function to execute when the operator is encountered
+//
+// Concurrency: This function is safe for concurrent use as it properly locks the operators map.
+func RegisterFunc3[A, B, C, R any](name string, fn func(A, B, C) (R, error)) {
+	registerFunc(name, []funcType{typeOf[A](), typeOf[B](), typeOf[C]()}, typeOf[R](), func(args []any) (any, error) {
+		return fn(as[A](args[0]), as[B](args[1]), as[C](args[2]))
+	})
+}
+
+func registerFunc(name string, params []funcType, result funcType, call func(args []any) (any, error)) {
+	signature := Signature{Result: result.name}
+	for _, param := range params {
+		signature.Args = append(signature.Args, param.name)
+	}
+
+	operatorsLock.Lock()
+	defer operatorsLock.Unlock()
+
+	signatures[name] = signature
+	operators[name] = func(e *evaluator, values, data any) any {
+		node := e.node
+
+		parsed := e.parseValues(values, data)
+
+		args, ok := parsed.([]any)
+		if !ok {
+			args = []any{parsed}
+		}
+
+		output, err := callFunc(name, params, args, call)
+		if err != nil {
+			panic(e.operatorError(name, node, err))
+		}
+
+		return output
+	}
+}
+
+func callFunc(name string, params []funcType, args []any, call func(args []any) (any, error)) (any, error) {
+	if len(args) != len(params) {
+		return nil, &ArityError{Operator: name, Expected: len(params), Got: len(args)}
+	}
+
+	converted := make([]any, len(args))
+	for i, param := range params {
+		value, ok := param.convert(args[i])
+		if !ok {
+			return nil, &ArgumentError{Operator: name, Index: i, Expected: param.name, Value: args[i]}
+		}
+		converted[i] = value
+	}
+
+	output, err := call(converted)
+	if err != nil {
+		return nil, err
+	}
+
+	if n, ok := output.(int); ok {
+		return float64(n), nil
+	}
+
+	return output, nil
+}
+
+// acceptsArgs reports whether the arguments of an operation, before being
+// evaluated, may fit the signature. Only the literals are checked.
+func (s Signature) acceptsArgs(values any) bool {
+	if _, ok := values.(map[string]any); ok {
+		// a single operation may return all the arguments
+		return true
+	}
+
+	args, ok := values.([]any)
+	if !ok {
+		args = []any{values}
+	}
+
+	if len(args) != len(s.Args) {
+		return false
+	}
+
+	for i, arg := range args {
+		if arg != nil && !isPrimitive(arg) {
+			continue
+		}
+
+		if _, ok := funcTypes[s.Args[i]].convert(arg); !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// funcType is a type supported by the typed operators.
+type funcType struct {
+	name    string
+	convert func(value any) (any, bool)
+}
+
+// maxSafeInteger is the largest integer a float64 holds exactly.
+const maxSafeInteger = 1<<53 - 1
+
+var funcTypes = map[string]funcType{
+	"number": {name: "number", convert: func(value any) (any, bool) {
+		n := javascript.ToNumber(value)
+		return n, !math.IsNaN(n)
+	}},
+	"integer": {name: "integer", convert: func(value any) (any, bool) {
+		n := javascript.ToNumber(value)
+		if math.IsNaN(n) || n != math.Trunc(n) || math.Abs(n) > maxSafeInteger {
+			return nil, false
+		}
+		return int(n), true
+	}},
+	"string": {name: "string", convert: func(value any) (any, bool) {
+		switch v := value.(type) {
+		case nil, string, float64:
+			return toString(v), true
+		case bool:
+			return fmt.Sprint(v), true
+		}
+		return nil, false
+	}},
+	"boolean": {name: "boolean", convert: func(value any) (any, bool) {
+		return javascript.IsTrue(value), true
+	}},
+	"array": {name: "array", convert: func(value any) (any, bool) {
+		v, ok := value.([]any)
+		return v, ok
+	}},
+	"object": {name: "object", convert: func(value any) (any, bool) {
+		v, ok := value.(map[string]any)
+		return v, ok
+	}},
+	"any": {name: "any", convert: func(value any) (any, bool) {
+		return value, true
+	}},
+}
+
+// typeOf returns the funcType of T, and panics when T isn't supported.
+func typeOf[T any]() funcType {
+	var name string
+
+	switch any(new(T)).(type) {
+	case *float64:
+		name = "number"
+	case *int:
+		name = "integer"
+	case *string:
+		name = "string"
+	case *bool:
+		name = "boolean"
+	case *[]any:
+		name = "array"
+	case *map[string]any:
+		name = "object"
+	case *any:
+		name = "any"
+	default:
+		panic(fmt.Sprintf("jsonlogic: the type %s is not supported by typed operators", reflect.TypeOf(new(T)).Elem()))
+	}
+
+	return funcTypes[name]
+}
+
+// as returns the value as T, or the zero value of T when the value is nil.
+func as[T any](value any) T {
+	v, _ := value.(T)
+	return v
+}
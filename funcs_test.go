@@ -0,0 +1,160 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestRegisterFunc(t *testing.T) {
+	jsonlogic.RegisterFunc1("typed_slugify", func(s string) (string, error) {
+		return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(s)), " ", "-"), nil
+	})
+	jsonlogic.RegisterFunc2("typed_round_to", func(n float64, digits int) (float64, error) {
+		if digits < 0 {
+			return 0, errors.New("digits must not be negative")
+		}
+		scale := math.Pow(10, float64(digits))
+		return math.Round(n*scale) / scale, nil
+	})
+	jsonlogic.RegisterFunc3("typed_pick", func(condition bool, a, b any) (any, error) {
+		if condition {
+			return a, nil
+		}
+		return b, nil
+	})
+	jsonlogic.RegisterFunc1("typed_count", func(values []any) (int, error) {
+		return len(values), nil
+	})
+	jsonlogic.RegisterFunc2("typed_get", func(object map[string]any, key string) (any, error) {
+		return object[key], nil
+	})
+
+	data := `{"title": "  Hello World ", "price": "3.14159", "digits": 2, "tags": ["a", "b"], "user": {"name": "ana"}}`
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"string":                  {rule: `{"typed_slugify": {"var": "title"}}`, expected: `"hello-world"`},
+		"string from a number":    {rule: `{"typed_slugify": [12.5]}`, expected: `"12.5"`},
+		"number from a string":    {rule: `{"typed_round_to": [{"var": "price"}, {"var": "digits"}]}`, expected: `3.14`},
+		"integer from a string":   {rule: `{"typed_round_to": [2.5, "0"]}`, expected: `3`},
+		"boolean from truthiness": {rule: `{"typed_pick": [{"var": "tags"}, "yes", "no"]}`, expected: `"yes"`},
+		"any keeps null":          {rule: `{"typed_pick": [0, "yes", null]}`, expected: `null`},
+		"array":                   {rule: `{"typed_count": [{"var": "tags"}]}`, expected: `2`},
+		"object":                  {rule: `{"typed_get": [{"var": "user"}, "name"]}`, expected: `"ana"`},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(fmt.Sprintf("SCENARIO:%s", name), func(t *testing.T) {
+			output, err := jsonlogic.ApplyRaw(json.RawMessage(scenario.rule), json.RawMessage(data))
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, string(output))
+		})
+	}
+
+	failures := map[string]struct {
+		rule  string
+		check func(t *testing.T, err error)
+	}{
+		"too few arguments": {
+			rule: `{"typed_round_to": [{"var": "price"}]}`,
+			check: func(t *testing.T, err error) {
+				var arityErr *jsonlogic.ArityError
+				if assert.ErrorAs(t, err, &arityErr) {
+					assert.Equal(t, 2, arityErr.Expected)
+					assert.Equal(t, 1, arityErr.Got)
+				}
+			},
+		},
+		"not a number": {
+			rule: `{"typed_round_to": [{"var": "title"}, 2]}`,
+			check: func(t *testing.T, err error) {
+				var argumentErr *jsonlogic.ArgumentError
+				if assert.ErrorAs(t, err, &argumentErr) {
+					assert.Equal(t, 0, argumentErr.Index)
+					assert.Equal(t, "number", argumentErr.Expected)
+					assert.Equal(t, "  Hello World ", argumentErr.Value)
+				}
+			},
+		},
+		"not an integer": {
+			rule: `{"typed_round_to": [1, {"var": "price"}]}`,
+			check: func(t *testing.T, err error) {
+				assert.EqualError(t, err, `The operator "typed_round_to" failed: The argument 1 of "typed_round_to" must be of type "integer", got "3.14159"`)
+			},
+		},
+		"not an array": {
+			rule: `{"typed_count": [{"var": "user"}]}`,
+			check: func(t *testing.T, err error) {
+				var argumentErr *jsonlogic.ArgumentError
+				assert.ErrorAs(t, err, &argumentErr)
+			},
+		},
+		"error of the function": {
+			rule: `{"if": [true, {"typed_round_to": [1, -1]}]}`,
+			check: func(t *testing.T, err error) {
+				var operatorErr *jsonlogic.OperatorError
+				if assert.ErrorAs(t, err, &operatorErr) {
+					assert.Equal(t, "/if/1", operatorErr.Path)
+					assert.EqualError(t, operatorErr.Err, "digits must not be negative")
+				}
+			},
+		},
+	}
+
+	for name, scenario := range failures {
+		t.Run(fmt.Sprintf("FAILURE:%s", name), func(t *testing.T) {
+			_, err := jsonlogic.ApplyRaw(json.RawMessage(scenario.rule), json.RawMessage(data))
+			scenario.check(t, err)
+		})
+	}
+}
+
+func TestRegisterFuncSignature(t *testing.T) {
+	jsonlogic.RegisterFunc2("typed_signature", func(n float64, s string) (bool, error) {
+		return true, nil
+	})
+
+	signature, ok := jsonlogic.LookupSignature("typed_signature")
+	assert.True(t, ok)
+	assert.Equal(t, jsonlogic.Signature{Args: []string{"number", "string"}, Result: "boolean"}, signature)
+
+	assert.True(t, jsonlogic.IsValid(strings.NewReader(`{"typed_signature": [{"var": "n"}, "a"]}`)))
+	assert.True(t, jsonlogic.IsValid(strings.NewReader(`{"typed_signature": ["1.5", 2]}`)))
+	assert.True(t, jsonlogic.IsValid(strings.NewReader(`{"typed_signature": {"var": "pair"}}`)))
+	assert.False(t, jsonlogic.IsValid(strings.NewReader(`{"typed_signature": [1]}`)))
+	assert.False(t, jsonlogic.IsValid(strings.NewReader(`{"typed_signature": [1, "a", 2]}`)))
+	assert.False(t, jsonlogic.IsValid(strings.NewReader(`{"typed_signature": ["one", "a"]}`)))
+	assert.False(t, jsonlogic.IsValid(strings.NewReader(`{"and": [true, {"typed_signature": [1]}]}`)))
+
+	_, ok = jsonlogic.LookupSignature("and")
+	assert.False(t, ok)
+	assert.Equal(t, signature, jsonlogic.Signatures()["typed_signature"])
+	assert.NotContains(t, jsonlogic.Signatures(), "and")
+
+	// registering the operator again without a signature drops it
+	jsonlogic.AddOperator("typed_signature", func(values, data any) any { return nil })
+	_, ok = jsonlogic.LookupSignature("typed_signature")
+	assert.False(t, ok)
+	assert.NotContains(t, jsonlogic.Signatures(), "typed_signature")
+	assert.True(t, jsonlogic.IsValid(strings.NewReader(`{"typed_signature": [1]}`)))
+}
+
+func TestRegisterFuncWithUnsupportedTypes(t *testing.T) {
+	assert.PanicsWithValue(t, "jsonlogic: the type int32 is not supported by typed operators", func() {
+		jsonlogic.RegisterFunc1("typed_unsupported", func(n int32) (any, error) { return n, nil })
+	})
+	assert.Panics(t, func() {
+		jsonlogic.RegisterFunc1("typed_unsupported", func(s string) (error, error) { return nil, nil })
+	})
+	assert.False(t, jsonlogic.IsValid(strings.NewReader(`{"typed_unsupported": [1]}`)))
+}
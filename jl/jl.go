@@ -0,0 +1,401 @@
+// Package jl builds JsonLogic rules in Go code:
+//
+//	rule := jl.And(
+//		jl.Gte(jl.Var("age"), 18),
+//		jl.In(jl.Var("country"), "BR", "PT"),
+//	)
+//	// {"and": [{">=": [{"var": "age"}, 18]}, {"in": [{"var": "country"}, ["BR", "PT"]]}]}
+//
+// The rules built are the values encoding/json decodes from their JSON, so
+// they can be passed to jsonlogic.ApplyInterface or jsonlogic.CompileInterface
+// as they are, and encode to standard JsonLogic. The arguments may be rules,
+// or literals of any type encoding/json encodes: numbers of every Go type
+// become float64, and slices and maps become []any and map[string]any. The
+// functions panic when given a literal encoding/json can't encode.
+package jl
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// Rule is an operation, as the object with the operator as its single key
+// and the arguments as its value.
+type Rule = map[string]any
+
+// Op builds an operation of any operator, including the custom ones.
+//
+//	jl.Op("slugify", jl.Var("title")) // {"slugify": [{"var": "title"}]}
+func Op(operator string, args ...any) Rule {
+	return Rule{operator: list(args)}
+}
+
+// Literal returns the value in the types used by rules, to build literals
+// holding Go values.
+//
+//	jl.Literal([]int{1, 2}) // [1, 2] as []any{1.0, 2.0}
+func Literal(value any) any {
+	switch v := value.(type) {
+	case nil, bool, float64, string:
+		return v
+	case []any:
+		return list(v)
+	case map[string]any:
+		object := make(map[string]any, len(v))
+		for key, item := range v {
+			object[key] = Literal(item)
+		}
+		return object
+	case json.Number:
+		n, err := v.Float64()
+		if err != nil {
+			panic(fmt.Sprintf("jl: invalid number %q", v))
+		}
+		return n
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		return rv.String()
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.IsNil() {
+			return nil
+		}
+		if rv.Type().Elem().Kind() != reflect.Uint8 {
+			items := make([]any, rv.Len())
+			for i := range items {
+				items[i] = Literal(rv.Index(i).Interface())
+			}
+			return items
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() == reflect.String && !rv.IsNil() {
+			object := make(map[string]any, rv.Len())
+			iter := rv.MapRange()
+			for iter.Next() {
+				object[iter.Key().String()] = Literal(iter.Value().Interface())
+			}
+			return object
+		}
+	}
+
+	// values encoding to JSON in their own way, like time.Time
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		panic(fmt.Sprintf("jl: %T can't be used in a rule: %s", value, err))
+	}
+
+	var decoded any
+	if err := json.Unmarshal(encoded, &decoded); err != nil {
+		panic(fmt.Sprintf("jl: %T can't be used in a rule: %s", value, err))
+	}
+
+	return decoded
+}
+
+func list(values []any) []any {
+	items := make([]any, len(values))
+	for i, v := range values {
+		items[i] = Literal(v)
+	}
+	return items
+}
+
+// Array builds an array, whose items may be rules.
+//
+//	jl.Array(1, jl.Var("a")) // [1, {"var": "a"}]
+func Array(items ...any) []any {
+	return list(items)
+}
+
+// Var reads a value of the data by its path, written in any of the syntaxes
+// of jsonlogic.Path, with an optional default value.
+//
+//	jl.Var("user.age")     // {"var": "user.age"}
+//	jl.Var("user.age", 18) // {"var": ["user.age", 18]}
+//	jl.Var("")             // {"var": ""}, the current element in iterations
+func Var(path any, def ...any) Rule {
+	if len(def) == 0 {
+		return Rule{"var": Literal(path)}
+	}
+	return Rule{"var": []any{Literal(path), Literal(def[0])}}
+}
+
+// Val reads a value of the current scope by the segments of its path.
+//
+//	jl.Val("user", "e-mail.address") // {"val": ["user", "e-mail.address"]}
+func Val(segments ...any) Rule {
+	return Op("val", segments...)
+}
+
+// ValAt reads a value of an enclosing scope: 1 is the data around the
+// innermost iteration, and -1 the iteration itself.
+//
+//	jl.ValAt(1, "limit") // {"val": [[1], "limit"]}
+func ValAt(scope int, segments ...any) Rule {
+	return Op("val", append([]any{[]any{float64(scope)}}, segments...)...)
+}
+
+// Missing lists the paths missing from the data.
+//
+//	jl.Missing("name", "age") // {"missing": ["name", "age"]}
+func Missing(paths ...string) Rule {
+	return Op("missing", stringList(paths)...)
+}
+
+// MissingSome lists the paths missing from the data when fewer than n are present.
+//
+//	jl.MissingSome(1, "email", "phone") // {"missing_some": [1, ["email", "phone"]]}
+func MissingSome(n int, paths ...string) Rule {
+	return Op("missing_some", n, stringList(paths))
+}
+
+func stringList(values []string) []any {
+	items := make([]any, len(values))
+	for i, v := range values {
+		items[i] = v
+	}
+	return items
+}
+
+// Let binds names to the results of the expressions for the body.
+//
+//	jl.Let(map[string]any{"gross": jl.Add(jl.Var("price"), jl.Var("tax"))}, jl.Gt(jl.Var("gross"), 100))
+func Let(bindings map[string]any, body any) Rule {
+	return Op("let", Literal(bindings), body)
+}
+
+// Try returns the result of the first argument evaluated without errors.
+//
+//	jl.Try(jl.Div(jl.Var("a"), jl.Var("b")), 0) // {"try": [{"/": [{"var": "a"}, {"var": "b"}]}, 0]}
+func Try(args ...any) Rule {
+	return Op("try", args...)
+}
+
+// Set returns a copy of the object with the key set to the value.
+//
+//	jl.Set(jl.Var("user"), "active", true) // {"set": [{"var": "user"}, "active", true]}
+func Set(object any, key string, value any) Rule {
+	return Op("set", object, key, value)
+}
+
+// If returns the value after the first true condition, or the last value
+// when none is true: conditions and values alternate.
+//
+//	jl.If(jl.Gte(jl.Var("age"), 18), "adult", "minor") // {"if": [{">=": [{"var": "age"}, 18]}, "adult", "minor"]}
+func If(args ...any) Rule {
+	return Op("if", args...)
+}
+
+// Ternary returns then when the condition is truthy, or else otherwise.
+//
+//	jl.Ternary(jl.Var("vip"), 0, 10) // {"?:": [{"var": "vip"}, 0, 10]}
+func Ternary(condition, then, otherwise any) Rule {
+	return Op("?:", condition, then, otherwise)
+}
+
+// And returns the first falsy argument, or the last one.
+func And(args ...any) Rule {
+	return Op("and", args...)
+}
+
+// Or returns the first truthy argument, or the last one.
+func Or(args ...any) Rule {
+	return Op("or", args...)
+}
+
+// Not returns whether the value is falsy: {"!": [value]}.
+func Not(value any) Rule {
+	return Op("!", value)
+}
+
+// Truthy returns whether the value is truthy: {"!!": [value]}.
+func Truthy(value any) Rule {
+	return Op("!!", value)
+}
+
+// Eq compares the values after converting them: {"==": [a, b]}.
+func Eq(a, b any) Rule {
+	return Op("==", a, b)
+}
+
+// Ne is the negation of Eq: {"!=": [a, b]}.
+func Ne(a, b any) Rule {
+	return Op("!=", a, b)
+}
+
+// StrictEq compares the values without converting them: {"===": [a, b]}.
+func StrictEq(a, b any) Rule {
+	return Op("===", a, b)
+}
+
+// StrictNe is the negation of StrictEq: {"!==": [a, b]}.
+func StrictNe(a, b any) Rule {
+	return Op("!==", a, b)
+}
+
+// Lt builds {"<": [a, b]}.
+func Lt(a, b any) Rule {
+	return Op("<", a, b)
+}
+
+// Lte builds {"<=": [a, b]}.
+func Lte(a, b any) Rule {
+	return Op("<=", a, b)
+}
+
+// Gt builds {">": [a, b]}.
+func Gt(a, b any) Rule {
+	return Op(">", a, b)
+}
+
+// Gte builds {">=": [a, b]}.
+func Gte(a, b any) Rule {
+	return Op(">=", a, b)
+}
+
+// Between tests whether the value is strictly between low and high:
+// {"<": [low, value, high]}.
+func Between(low, value, high any) Rule {
+	return Op("<", low, value, high)
+}
+
+// BetweenInclusive tests whether the value is between low and high, both
+// included: {"<=": [low, value, high]}.
+func BetweenInclusive(low, value, high any) Rule {
+	return Op("<=", low, value, high)
+}
+
+// In tests whether the value is one of the values listed.
+//
+//	jl.In(jl.Var("country"), "BR", "PT") // {"in": [{"var": "country"}, ["BR", "PT"]]}
+func In(value any, values ...any) Rule {
+	return Op("in", value, list(values))
+}
+
+// InValue tests whether the value is an element of the array, or a
+// substring of the string, given as a rule or a literal.
+//
+//	jl.InValue("admin", jl.Var("roles")) // {"in": ["admin", {"var": "roles"}]}
+func InValue(value, arrayOrString any) Rule {
+	return Op("in", value, arrayOrString)
+}
+
+// Cat concatenates the values as strings.
+func Cat(args ...any) Rule {
+	return Op("cat", args...)
+}
+
+// Substr returns the part of the string from start, of the given length
+// when there is one. Negative numbers count from the end.
+//
+//	jl.Substr(jl.Var("code"), 0, 3) // {"substr": [{"var": "code"}, 0, 3]}
+func Substr(s, start any, length ...any) Rule {
+	return Op("substr", append([]any{s, start}, length...)...)
+}
+
+// Add builds {"+": args}, which converts a single argument to a number.
+func Add(args ...any) Rule {
+	return Op("+", args...)
+}
+
+// Sub builds {"-": args}, which negates a single argument.
+func Sub(args ...any) Rule {
+	return Op("-", args...)
+}
+
+// Mul builds {"*": args}.
+func Mul(args ...any) Rule {
+	return Op("*", args...)
+}
+
+// Div builds {"/": [a, b]}.
+func Div(a, b any) Rule {
+	return Op("/", a, b)
+}
+
+// Mod builds {"%": [a, b]}.
+func Mod(a, b any) Rule {
+	return Op("%", a, b)
+}
+
+// Abs builds {"abs": value}.
+func Abs(value any) Rule {
+	return Rule{"abs": Literal(value)}
+}
+
+// Max builds {"max": args}.
+func Max(args ...any) Rule {
+	return Op("max", args...)
+}
+
+// Min builds {"min": args}.
+func Min(args ...any) Rule {
+	return Op("min", args...)
+}
+
+// Merge flattens the arrays given into one.
+func Merge(args ...any) Rule {
+	return Op("merge", args...)
+}
+
+// Map applies the body to every element of the array.
+//
+//	jl.Map(jl.Var("items"), jl.Mul(jl.Var("price"), 2)) // {"map": [{"var": "items"}, {"*": [{"var": "price"}, 2]}]}
+func Map(array, body any) Rule {
+	return Op("map", array, body)
+}
+
+// Filter keeps the elements of the array for which the body is truthy.
+func Filter(array, body any) Rule {
+	return Op("filter", array, body)
+}
+
+// Reduce combines the elements of the array, read by the body as
+// {"var": "current"}, with the result so far, read as {"var": "accumulator"}
+// and starting at initial.
+//
+//	jl.Reduce(jl.Var("items"), jl.Add(jl.Var("current"), jl.Var("accumulator")), 0)
+func Reduce(array, body, initial any) Rule {
+	return Op("reduce", array, body, initial)
+}
+
+// All tests whether the body is truthy for every element of a non-empty array.
+func All(array, body any) Rule {
+	return Op("all", array, body)
+}
+
+// Some tests whether the body is truthy for an element of the array.
+func Some(array, body any) Rule {
+	return Op("some", array, body)
+}
+
+// None tests whether the body is falsy for every element of the array.
+func None(array, body any) Rule {
+	return Op("none", array, body)
+}
+
+// ContainsAll tests whether every element of values is in array.
+func ContainsAll(array, values any) Rule {
+	return Op("contains_all", array, values)
+}
+
+// ContainsAny tests whether an element of values is in array.
+func ContainsAny(array, values any) Rule {
+	return Op("contains_any", array, values)
+}
+
+// ContainsNone tests whether no element of values is in array.
+func ContainsNone(array, values any) Rule {
+	return Op("contains_none", array, values)
+}
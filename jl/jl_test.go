@@ -0,0 +1,113 @@
+package jl_test
+
+import (
+	"encoding/json"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/jl"
+)
+
+func TestBuilders(t *testing.T) {
+	scenarios := map[string]struct {
+		rule     jl.Rule
+		expected string
+	}{
+		"var":          {rule: jl.Var("user.age"), expected: `{"var": "user.age"}`},
+		"var default":  {rule: jl.Var("user.age", 18), expected: `{"var": ["user.age", 18]}`},
+		"val":          {rule: jl.Val("user", "e-mail.address"), expected: `{"val": ["user", "e-mail.address"]}`},
+		"val at":       {rule: jl.ValAt(1, "limit"), expected: `{"val": [[1], "limit"]}`},
+		"missing":      {rule: jl.Missing("a", "b"), expected: `{"missing": ["a", "b"]}`},
+		"missing some": {rule: jl.MissingSome(1, "email", "phone"), expected: `{"missing_some": [1, ["email", "phone"]]}`},
+		"and":          {rule: jl.And(jl.Gte(jl.Var("age"), 18), jl.In(jl.Var("country"), "BR", "PT")), expected: `{"and": [{">=": [{"var": "age"}, 18]}, {"in": [{"var": "country"}, ["BR", "PT"]]}]}`},
+		"in value":     {rule: jl.InValue("admin", jl.Var("roles")), expected: `{"in": ["admin", {"var": "roles"}]}`},
+		"between":      {rule: jl.Between(0, jl.Var("x"), 10), expected: `{"<": [0, {"var": "x"}, 10]}`},
+		"abs":          {rule: jl.Abs(jl.Var("x")), expected: `{"abs": {"var": "x"}}`},
+		"substr":       {rule: jl.Substr(jl.Var("code"), 0, 3), expected: `{"substr": [{"var": "code"}, 0, 3]}`},
+		"let":          {rule: jl.Let(map[string]any{"gross": jl.Add(jl.Var("price"), 1)}, jl.Gt(jl.Var("gross"), 100)), expected: `{"let": [{"gross": {"+": [{"var": "price"}, 1]}}, {">": [{"var": "gross"}, 100]}]}`},
+		"reduce":       {rule: jl.Reduce(jl.Var("items"), jl.Add(jl.Var("current"), jl.Var("accumulator")), 0), expected: `{"reduce": [{"var": "items"}, {"+": [{"var": "current"}, {"var": "accumulator"}]}, 0]}`},
+		"custom":       {rule: jl.Op("slugify", jl.Var("title")), expected: `{"slugify": [{"var": "title"}]}`},
+		"literals":     {rule: jl.Eq(jl.Array(uint8(1), []string{"a"}, map[string]int{"b": 2}), nil), expected: `{"==": [[1, ["a"], {"b": 2}], null]}`},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			encoded, err := json.Marshal(scenario.rule)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, string(encoded))
+
+			var decoded any
+			assert.NoError(t, json.Unmarshal([]byte(scenario.expected), &decoded))
+			assert.Equal(t, decoded, any(scenario.rule))
+		})
+	}
+}
+
+func TestBuildersCoverEveryBuiltInOperator(t *testing.T) {
+	rules := []jl.Rule{
+		jl.Var("a"), jl.Val("a"), jl.Missing("a"), jl.MissingSome(1, "a"),
+		jl.Let(map[string]any{"a": 1}, 1), jl.Try(1), jl.Set(jl.Var("a"), "b", 1),
+		jl.If(true, 1, 2), jl.Ternary(true, 1, 2), jl.And(true), jl.Or(true), jl.Not(true), jl.Truthy(true),
+		jl.Eq(1, 1), jl.Ne(1, 1), jl.StrictEq(1, 1), jl.StrictNe(1, 1),
+		jl.Lt(1, 2), jl.Lte(1, 2), jl.Gt(1, 2), jl.Gte(1, 2),
+		jl.In(1, 1), jl.Cat("a"), jl.Substr("a", 0),
+		jl.Add(1), jl.Sub(1), jl.Mul(1), jl.Div(1, 1), jl.Mod(1, 1), jl.Abs(1), jl.Max(1), jl.Min(1),
+		jl.Merge(1), jl.Map(jl.Var("a"), 1), jl.Filter(jl.Var("a"), 1), jl.Reduce(jl.Var("a"), 1, 0),
+		jl.All(jl.Var("a"), 1), jl.Some(jl.Var("a"), 1), jl.None(jl.Var("a"), 1),
+		jl.ContainsAll(jl.Array(), jl.Array()), jl.ContainsAny(jl.Array(), jl.Array()), jl.ContainsNone(jl.Array(), jl.Array()),
+	}
+
+	var operators []string
+	for _, rule := range rules {
+		for operator := range rule {
+			operators = append(operators, operator)
+		}
+	}
+	sort.Strings(operators)
+
+	assert.Equal(t, []string{
+		"!", "!!", "!=", "!==", "%", "*", "+", "-", "/", "<", "<=", "==", "===", ">", ">=", "?:",
+		"abs", "all", "and", "cat", "contains_all", "contains_any", "contains_none", "filter", "if", "in",
+		"let", "map", "max", "merge", "min", "missing", "missing_some", "none", "or", "reduce", "set",
+		"some", "substr", "try", "val", "var",
+	}, operators)
+
+	for _, rule := range rules {
+		assert.True(t, jsonlogic.ValidateJsonLogic(rule), "%v", rule)
+	}
+}
+
+func TestRulesCanBeApplied(t *testing.T) {
+	rule := jl.If(
+		jl.And(jl.Gte(jl.Var("age"), 18), jl.In(jl.Var("country"), "BR", "PT")),
+		jl.Cat("welcome ", jl.Var("name")),
+		jl.Sub(18, jl.Var("age")),
+	)
+
+	result, err := jsonlogic.ApplyInterface(rule, map[string]any{"age": float64(21), "country": "PT", "name": "Ana"})
+	assert.NoError(t, err)
+	assert.Equal(t, "welcome Ana", result)
+
+	compiled, err := jsonlogic.CompileInterface(rule)
+	assert.NoError(t, err)
+
+	result, err = compiled.Apply(map[string]any{"age": float64(15), "country": "BR"})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(3), result)
+}
+
+func TestLiteral(t *testing.T) {
+	assert.Equal(t, float64(3), jl.Literal(int64(3)))
+	assert.Equal(t, float64(1.5), jl.Literal(float32(1.5)))
+	assert.Equal(t, []any{float64(1), float64(2)}, jl.Literal([2]int{1, 2}))
+	assert.Equal(t, "2024-01-02T00:00:00Z", jl.Literal(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)))
+	assert.Nil(t, jl.Literal([]int(nil)))
+
+	assert.PanicsWithValue(t, "jl: chan int can't be used in a rule: json: unsupported type: chan int", func() {
+		jl.Literal(make(chan int))
+	})
+}
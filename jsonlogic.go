@@ -27,7 +27,6 @@ import (
 	"fmt"
 	"io"
 	"strings"
-
 )
 
 // Apply reads a rule and data from `io.Reader`, applies the rule to the data
@@ -127,7 +126,87 @@ func ApplyInterface(rule, data any) (any, error) {
 	return applyInterfaceUnguarded(rule, data)
 }
 
+// Options configures the evaluation of a rule.
+type Options struct {
+	// Strict disables the implicit conversions between types. Arithmetic on
+	// values that aren't numbers, comparisons between values of different
+	// types and equality between arrays or objects fail with a *TypeError, and
+	// reading a variable that doesn't exist, without a default value, fails
+	// with a *MissingVariableError.
+	Strict bool
+
+	// Profile selects the behavior of the edge cases in which implementations
+	// of JsonLogic disagree. The default is ProfileLegacy.
+	Profile Profile
+}
+
+// ApplyRawWithOptions works like ApplyRaw, evaluating the rule with the given options.
+//
+// Parameters:
+//   - rule: json.RawMessage representing the transformation rule to be applied
+//   - data: json.RawMessage containing the input data to transform
+//   - opts: settings of the evaluation
+//
+// Returns:
+//   - output: json.RawMessage containing the transformed data
+//   - err: error if the transformation fails or if type assertions are invalid
+func ApplyRawWithOptions(rule, data json.RawMessage, opts Options) (json.RawMessage, error) {
+	if data == nil {
+		data = json.RawMessage("{}")
+	}
+
+	var _rule any
+	var _data any
+
+	err := json.Unmarshal(rule, &_rule)
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(data, &_data)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := newEvaluatorWithOptions(opts).evaluate(_rule, _data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&result)
+}
+
+// ApplyInterfaceWithOptions works like ApplyInterface, evaluating the rule with the given options.
+//
+// Parameters:
+//   - rule: interface{} representing the transformation rule to be applied
+//   - data: interface{} containing the input data to transform
+//   - opts: settings of the evaluation
+//
+// Returns:
+//   - output: interface{} containing the transformed data
+//   - err: error if unsupported types are detected or if the transformation fails
+func ApplyInterfaceWithOptions(rule, data any, opts Options) (any, error) {
+	if err := scanForUnsupportedTypes(rule); err != nil {
+		return nil, err
+	}
+	if err := scanForUnsupportedTypes(data); err != nil {
+		return nil, err
+	}
+	return newEvaluatorWithOptions(opts).evaluate(rule, data)
+}
+
 func applyInterfaceUnguarded(rule, data any) (output any, err error) {
+	return newEvaluator().evaluate(rule, data)
+}
+
+// evaluate applies the rule to the data, turning the panics raised by the
+// operators into an error.
+func (e *evaluator) evaluate(rule, data any) (output any, err error) {
+	e.scopes = append(e.scopes[:0], scope{data: data})
+	e.bindings = e.bindings[:0]
+	e.root = rule
+
 	defer func() {
 		if e := recover(); e != nil {
 			// fmt.Println("stacktrace from panic: \n" + string(debug.Stack()))
@@ -136,14 +215,14 @@ func applyInterfaceUnguarded(rule, data any) (output any, err error) {
 	}()
 
 	if m, ok := rule.(map[string]any); ok {
-		return apply(m, data), err
+		return e.apply(m, data), err
 	}
 
 	if s, ok := rule.([]any); ok {
 		parsed := make([]any, 0, len(s))
 
 		for _, value := range s {
-			parsed = append(parsed, parseValues(value, data))
+			parsed = append(parsed, e.parseValues(value, data))
 		}
 
 		return any(parsed), nil
@@ -209,13 +288,28 @@ func GetJsonLogicWithSolvedVars(rule, data json.RawMessage) ([]byte, error) {
 	return solveVarsBackToJsonLogic(_rule, _data)
 }
 
-func parseValues(values, data any) any {
+// guarded applies the rule to the data like parseValues, but returns the
+// errors raised by the operators instead of panicking.
+func (e *evaluator) guarded(rule, data any) (output any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			if err, ok = r.(error); !ok {
+				panic(r)
+			}
+		}
+	}()
+
+	return e.parseValues(rule, data), nil
+}
+
+func (e *evaluator) parseValues(values, data any) any {
 	if values == nil || isPrimitive(values) {
 		return values
 	}
 
 	if m, ok := values.(map[string]any); ok {
-		return apply(m, data)
+		return e.apply(m, data)
 	}
 
 	inputSlice := values.([]any)
@@ -228,16 +322,16 @@ func parseValues(values, data any) any {
 
 	for _, value := range inputSlice {
 		if m, ok := value.(map[string]any); ok {
-			parsed = append(parsed, apply(m, data))
+			parsed = append(parsed, e.apply(m, data))
 		} else {
-			parsed = append(parsed, parseValues(value, data))
+			parsed = append(parsed, e.parseValues(value, data))
 		}
 	}
 
 	return parsed
 }
 
-func apply(rules, data any) any {
+func (e *evaluator) apply(rules, data any) any {
 	ruleMap := rules.(map[string]any)
 
 	// A map with more than 1 key counts as a primitive so it's time to end recursion
@@ -245,8 +339,27 @@ func apply(rules, data any) any {
 		return ruleMap
 	}
 
+	if e.coverage != nil {
+		e.coverage.enter(ruleMap)
+	}
+
+	key, memoized := e.memoized(ruleMap)
+	if memoized {
+		if result, ok := e.memo[key]; ok {
+			return result
+		}
+	}
+
 	for operator, values := range ruleMap {
-		return operation(operator, values, data)
+		e.node = ruleMap
+		result := e.operation(operator, values, data)
+		if memoized {
+			e.memo[key] = result
+		}
+		if e.coverage != nil {
+			e.coverage.record(ruleMap, result)
+		}
+		return result
 	}
 
 	return make(map[string]any)
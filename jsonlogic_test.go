@@ -3,6 +3,7 @@ package jsonlogic_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -10,27 +11,24 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
-	"github.com/diegoholiveira/jsonlogic/v3/internal"
+	"github.com/diegoholiveira/jsonlogic/v3/jsonlogictest"
 )
 
 func TestRulesFromJsonLogic(t *testing.T) {
-	suites := map[string][]internal.Test{
-		"Official": internal.GetScenariosFromOfficialTestSuite(),
-		"Proposed in https://github.com/jwadhams/json-logic/pull/48": internal.GetScenariosFromProposedOfficialTestSuite(),
-	}
-
-	for suiteName, tests := range suites {
-		t.Run(suiteName, func(t *testing.T) {
-			for _, test := range tests {
-				t.Run(fmt.Sprintf("%s_%d", test.Scenario, test.Index), func(t *testing.T) {
-					result, err := jsonlogic.ApplyInterface(test.Rule, test.Data)
-					if err != nil {
-						t.Fatal(err)
-					}
-
-					assert.Equal(t, test.Expected, result, "Applying rule %v to data %v", toJSON(test.Rule), toJSON(test.Data))
-				})
+	suites := map[string]func() (jsonlogictest.Suite, error){
+		"official":  jsonlogictest.Official,
+		"community": jsonlogictest.Community,
+		"rules":     func() (jsonlogictest.Suite, error) { return jsonlogictest.LoadFile("testdata/suites/rules.json") },
+	}
+
+	for name, load := range suites {
+		t.Run(name, func(t *testing.T) {
+			suite, err := load()
+			if err != nil {
+				t.Fatal(err)
 			}
+
+			jsonlogictest.Test(t, suite, jsonlogic.ApplyInterface)
 		})
 	}
 }
@@ -645,6 +643,199 @@ func TestAddOperator(t *testing.T) {
 	assert.JSONEq(t, expected, result.String())
 }
 
+func TestAddLazyOperator(t *testing.T) {
+	// first_match returns the result of the first rule that isn't null
+	jsonlogic.AddLazyOperator("first_match", func(args []any, ev jsonlogic.Evaluator) (any, error) {
+		for _, arg := range args {
+			v, err := ev.Evaluate(arg)
+			if err != nil {
+				return nil, err
+			}
+			if v != nil {
+				return v, nil
+			}
+		}
+		return nil, nil
+	})
+
+	// count_where counts the elements of the array matching the rule
+	jsonlogic.AddLazyOperator("count_where", func(args []any, ev jsonlogic.Evaluator) (any, error) {
+		if len(args) != 2 {
+			return nil, fmt.Errorf("count_where expects 2 arguments, got %d", len(args))
+		}
+
+		list, err := ev.Evaluate(args[0])
+		if err != nil {
+			return nil, err
+		}
+
+		elements, _ := list.([]any)
+
+		count := 0
+		for _, element := range elements {
+			v, err := ev.EvaluateWith(args[1], element)
+			if err != nil {
+				return nil, err
+			}
+			if v == true {
+				count++
+			}
+		}
+		return float64(count), nil
+	})
+
+	// retry_default returns the second rule when the first one fails
+	jsonlogic.AddLazyOperator("retry_default", func(args []any, ev jsonlogic.Evaluator) (any, error) {
+		v, err := ev.Evaluate(args[0])
+		if err != nil {
+			return ev.Evaluate(args[1])
+		}
+		return v, nil
+	})
+
+	data := `{"name": null, "nickname": "bia", "limit": 2, "scores": [1, 3, 5]}`
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"first_match short-circuits": {
+			rule:     `{"first_match": [{"var": "name"}, {"var": "nickname"}, {"reduce": [[1], {"var": "current"}, null]}]}`,
+			expected: `"bia"`,
+		},
+		"first_match with a single rule": {
+			rule:     `{"first_match": {"var": "nickname"}}`,
+			expected: `"bia"`,
+		},
+		"count_where evaluates against the elements": {
+			rule:     `{"count_where": [{"var": "scores"}, {">": [{"var": ""}, {"val": [[1], "limit"]}]}]}`,
+			expected: `2`,
+		},
+		"count_where inside map": {
+			rule:     `{"map": [[[1, 2], [3]], {"count_where": [{"var": ""}, {"<": [{"var": ""}, 3]}]}]}`,
+			expected: `[2, 0]`,
+		},
+		"retry_default catches errors": {
+			rule:     `{"retry_default": [{"reduce": [[1], {"var": "current"}, null]}, "fallback"]}`,
+			expected: `"fallback"`,
+		},
+		"retry_default keeps results": {
+			rule:     `{"retry_default": [{"var": "limit"}, "fallback"]}`,
+			expected: `2`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(fmt.Sprintf("SCENARIO:%s", name), func(t *testing.T) {
+			assert.True(t, jsonlogic.IsValid(strings.NewReader(scenario.rule)))
+
+			var result bytes.Buffer
+			err := jsonlogic.Apply(strings.NewReader(scenario.rule), strings.NewReader(data), &result)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, result.String())
+		})
+	}
+
+	_, err := jsonlogic.ApplyRaw(json.RawMessage(`{"count_where": [[1]]}`), nil)
+	assert.EqualError(t, err, `The operator "count_where" failed: count_where expects 2 arguments, got 1`)
+
+	_, err = jsonlogic.ApplyRaw(json.RawMessage(`{"first_match": [{"reduce": [[1], {"var": "current"}, null]}]}`), nil)
+	assert.ErrorAs(t, err, &jsonlogic.ErrReduceDataType{})
+}
+
+func TestAddOperatorWithError(t *testing.T) {
+	errNotFound := errors.New("not found")
+
+	jsonlogic.AddOperatorWithError("lookup", func(values, data any) (any, error) {
+		prices := map[string]any{"apple": float64(3), "pear": float64(4)}
+
+		price, ok := prices[fmt.Sprint(values)]
+		if !ok {
+			return nil, fmt.Errorf("lookup %v: %w", values, errNotFound)
+		}
+		return price, nil
+	})
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+		err      string
+		path     string
+	}{
+		"succeeds": {
+			rule:     `{"+": [{"lookup": "apple"}, {"lookup": "pear"}]}`,
+			expected: `7`,
+		},
+		"fails at the root": {
+			rule: `{"lookup": "kiwi"}`,
+			err:  `The operator "lookup" failed: lookup kiwi: not found`,
+			path: "",
+		},
+		"fails inside other operations": {
+			rule: `{"and": [true, {">": [{"lookup": {"var": "fruit"}}, 1]}]}`,
+			err:  `The operator "lookup" at "/and/1/>/0" failed: lookup kiwi: not found`,
+			path: "/and/1/>/0",
+		},
+		"fails inside map": {
+			rule: `{"map": [["apple", "kiwi"], {"lookup": {"var": ""}}]}`,
+			err:  `The operator "lookup" at "/map/1" failed: lookup kiwi: not found`,
+			path: "/map/1",
+		},
+		"try catches the error": {
+			rule:     `{"try": [{"lookup": {"var": "fruit"}}, 0]}`,
+			expected: `0`,
+		},
+		"try returns the first success": {
+			rule:     `{"try": [{"lookup": "kiwi"}, {"lookup": "pear"}, {"lookup": "apple"}]}`,
+			expected: `4`,
+		},
+		"try keeps results": {
+			rule:     `{"try": [{"lookup": "apple"}, 0]}`,
+			expected: `3`,
+		},
+		"try catches errors of built-in operators": {
+			rule:     `{"try": [{"reduce": [[1], {"var": "current"}, null]}, "fallback"]}`,
+			expected: `"fallback"`,
+		},
+		"try raises the last error": {
+			rule: `{"try": [{"lookup": "kiwi"}, {"lookup": "fig"}]}`,
+			err:  `The operator "lookup" at "/try/1" failed: lookup fig: not found`,
+			path: "/try/1",
+		},
+		"try inside map": {
+			rule:     `{"map": [["apple", "kiwi"], {"try": [{"lookup": {"var": ""}}, null]}]}`,
+			expected: `[3, null]`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(fmt.Sprintf("SCENARIO:%s", name), func(t *testing.T) {
+			output, err := jsonlogic.ApplyRaw(json.RawMessage(scenario.rule), json.RawMessage(`{"fruit": "kiwi"}`))
+
+			if scenario.err == "" {
+				assert.NoError(t, err)
+				assert.JSONEq(t, scenario.expected, string(output))
+				return
+			}
+
+			assert.EqualError(t, err, scenario.err)
+			assert.ErrorIs(t, err, errNotFound)
+
+			var operatorErr *jsonlogic.OperatorError
+			if assert.ErrorAs(t, err, &operatorErr) {
+				assert.Equal(t, "lookup", operatorErr.Operator)
+				assert.Equal(t, scenario.path, operatorErr.Path)
+			}
+		})
+	}
+
+	var rule any
+	assert.NoError(t, json.Unmarshal([]byte(`{"or": [false, {"lookup": "kiwi"}]}`), &rule))
+	_, err := jsonlogic.ApplyInterface(rule, nil)
+	assert.ErrorIs(t, err, errNotFound)
+	assert.EqualError(t, err, `The operator "lookup" at "/or/1" failed: lookup kiwi: not found`)
+}
+
 func TestInWithOneParam(t *testing.T) {
 	rule := strings.NewReader(`{"in": [ "Ringo" ]}`)
 	data := strings.NewReader(`null`)
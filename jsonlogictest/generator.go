@@ -0,0 +1,617 @@
+package jsonlogictest
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"reflect"
+	"sort"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// Generator produces random rules that are valid and well typed, and random
+// data satisfying the variables they read, for property-based tests of custom
+// operators and of code translating or evaluating rules.
+//
+// The rules combine the built-in operators with the operators registered with
+// jsonlogic.RegisterFunc1, RegisterFunc2 or RegisterFunc3, whose signatures
+// tell the types of their arguments and results. The arrays produced by the
+// built-in operators hold numbers. A Generator isn't safe for concurrent use.
+type Generator struct {
+	// MaxDepth bounds the nesting of the operations, 4 when not positive.
+	MaxDepth int
+
+	// Operators restricts the operators used, by name. Every supported
+	// built-in operator and every typed operator is used when it's empty.
+	Operators []string
+
+	rand *rand.Rand
+
+	// types holds the type of the variables of the generated rules, by path.
+	types map[string]string
+	paths int
+
+	// body is set while generating the body of an iteration, in which
+	// {"var": ""} is the number being iterated.
+	body bool
+}
+
+// NewGenerator returns a Generator whose output is determined by the seed.
+func NewGenerator(seed int64) *Generator {
+	return NewGeneratorFromRand(rand.New(rand.NewSource(seed)))
+}
+
+// NewGeneratorFromRand returns a Generator drawing its choices from r.
+func NewGeneratorFromRand(r *rand.Rand) *Generator {
+	return &Generator{
+		rand:  r,
+		types: make(map[string]string),
+	}
+}
+
+// types the generator produces rules of.
+var ruleTypes = []string{"number", "string", "boolean", "array"}
+
+// Rule returns a random rule producing a number, a string, a boolean or an array.
+func (g *Generator) Rule() any {
+	return g.RuleOfType(ruleTypes[g.rand.Intn(len(ruleTypes))])
+}
+
+// RuleOfType returns a random rule producing a value of the given type, named
+// like in jsonlogic.Signature. Only variables and typed operators produce objects.
+func (g *Generator) RuleOfType(typ string) any {
+	return g.expr(typ, 0)
+}
+
+// Data returns a random document holding a value of the right type for every
+// variable the rule reads. The type of the variables that weren't generated
+// by g is chosen at random.
+func (g *Generator) Data(rule any) any {
+	data := make(map[string]any)
+
+	for _, path := range jsonlogic.Variables(rule) {
+		if len(path) == 0 || path.HasWildcard() {
+			continue
+		}
+
+		typ, ok := g.types[path.String()]
+		if !ok {
+			typ = "any"
+		}
+
+		set(data, path, g.value(typ))
+	}
+
+	return data
+}
+
+// Case returns a random rule with data for it.
+func (g *Generator) Case() RandomCase {
+	rule := g.Rule()
+	return RandomCase{Rule: rule, Data: g.Data(rule)}
+}
+
+// RandomCase is a random rule with data satisfying its variables. It
+// implements quick.Generator, so properties checked with testing/quick can
+// take it as an argument:
+//
+//	quick.Check(func(c jsonlogictest.RandomCase) bool {
+//		_, err := jsonlogic.ApplyInterface(c.Rule, c.Data)
+//		return err == nil
+//	}, nil)
+type RandomCase struct {
+	Rule any
+	Data any
+}
+
+// Generate returns a random case, with operations nested deeper as the size grows.
+func (RandomCase) Generate(r *rand.Rand, size int) reflect.Value {
+	g := NewGeneratorFromRand(r)
+	g.MaxDepth = 1 + size/10
+	if g.MaxDepth > 6 {
+		g.MaxDepth = 6
+	}
+
+	return reflect.ValueOf(g.Case())
+}
+
+// GoString shows the case as JSON in the failures reported by testing/quick.
+func (c RandomCase) GoString() string {
+	return fmt.Sprintf("RandomCase{Rule: %s, Data: %s}", toJSON(c.Rule), toJSON(c.Data))
+}
+
+func (g *Generator) maxDepth() int {
+	if g.MaxDepth <= 0 {
+		return 4
+	}
+	return g.MaxDepth
+}
+
+func (g *Generator) expr(typ string, depth int) any {
+	if depth >= g.maxDepth() || g.rand.Intn(4) == 0 {
+		return g.leaf(typ)
+	}
+
+	candidates := g.operators(typ)
+	if len(candidates) == 0 {
+		return g.leaf(typ)
+	}
+
+	name := candidates[g.rand.Intn(len(candidates))]
+	if b, ok := builtins[name]; ok {
+		return map[string]any{name: b.args(g, typ, depth+1)}
+	}
+
+	signature, _ := jsonlogic.LookupSignature(name)
+	args := make([]any, len(signature.Args))
+	for i, arg := range signature.Args {
+		args[i] = g.expr(arg, depth+1)
+	}
+
+	return map[string]any{name: args}
+}
+
+// operators returns the names of the operators producing the type, sorted so
+// the choices only depend on the source of g.
+func (g *Generator) operators(typ string) []string {
+	allowed := func(string) bool { return true }
+	if len(g.Operators) > 0 {
+		names := make(map[string]bool, len(g.Operators))
+		for _, name := range g.Operators {
+			names[name] = true
+		}
+		allowed = func(name string) bool { return names[name] }
+	}
+
+	var candidates []string
+
+	for name, b := range builtins {
+		if allowed(name) && (b.result == typ || b.result == "" || typ == "any") {
+			candidates = append(candidates, name)
+		}
+	}
+
+	for name, signature := range jsonlogic.Signatures() {
+		if _, ok := builtins[name]; ok || !allowed(name) || !generable(signature) {
+			continue
+		}
+		if produces(signature.Result, typ) {
+			candidates = append(candidates, name)
+		}
+	}
+
+	sort.Strings(candidates)
+
+	return candidates
+}
+
+// produces reports whether the result of a typed operator can be used where
+// a value of the type is expected. Arrays are only known to hold numbers when
+// produced by the built-in operators.
+func produces(result, typ string) bool {
+	switch typ {
+	case "any":
+		return true
+	case "number":
+		return result == "number" || result == "integer"
+	}
+	return result == typ && typ != "array"
+}
+
+// generable reports whether every type of the signature is known.
+func generable(signature jsonlogic.Signature) bool {
+	for _, typ := range append([]string{signature.Result}, signature.Args...) {
+		switch typ {
+		case "number", "integer", "string", "boolean", "array", "object", "any":
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func (g *Generator) leaf(typ string) any {
+	if typ == "any" {
+		typ = ruleTypes[g.rand.Intn(len(ruleTypes))]
+	}
+
+	if g.body {
+		// variables outside the data of the iteration aren't reported by
+		// jsonlogic.Variables, so the bodies only read the elements
+		if typ == "number" && g.rand.Intn(2) == 0 {
+			return map[string]any{"var": ""}
+		}
+		return g.value(typ)
+	}
+
+	if typ == "object" || g.rand.Intn(3) == 0 {
+		return map[string]any{"var": g.variable(typ)}
+	}
+
+	return g.value(typ)
+}
+
+// variable returns the path of a variable of the type, reusing one of the
+// paths already holding that type half the time.
+func (g *Generator) variable(typ string) string {
+	if g.rand.Intn(2) == 0 {
+		var paths []string
+		for path, t := range g.types {
+			if t == typ {
+				paths = append(paths, path)
+			}
+		}
+		if len(paths) > 0 {
+			sort.Strings(paths)
+			return paths[g.rand.Intn(len(paths))]
+		}
+	}
+
+	g.paths++
+	path := fmt.Sprintf("v%d", g.paths)
+	if g.rand.Intn(3) == 0 {
+		// containers are never read as values, so the paths never clash
+		path = fmt.Sprintf("%s.%s", containers[g.rand.Intn(len(containers))], path)
+	}
+	g.types[path] = typ
+
+	return path
+}
+
+var containers = []string{"user", "order", "items"}
+
+var words = []string{"", "a", "b", "abc", "hello", "JSON", "logic", "ção"}
+
+// value returns a random value of the type.
+func (g *Generator) value(typ string) any {
+	switch typ {
+	case "number":
+		n := float64(g.rand.Intn(21) - 10)
+		if g.rand.Intn(4) == 0 {
+			n += 0.5
+		}
+		return n
+	case "integer":
+		return float64(g.rand.Intn(21) - 10)
+	case "string":
+		return words[g.rand.Intn(len(words))]
+	case "boolean":
+		return g.rand.Intn(2) == 0
+	case "array":
+		values := make([]any, g.rand.Intn(4))
+		for i := range values {
+			values[i] = g.value("number")
+		}
+		return values
+	case "object":
+		return map[string]any{
+			"id":   g.value("integer"),
+			"name": g.value("string"),
+		}
+	}
+
+	return g.value([]string{"number", "string", "boolean"}[g.rand.Intn(3)])
+}
+
+// nonZero returns a number that can divide another one.
+func (g *Generator) nonZero() any {
+	n := float64(g.rand.Intn(10) + 1)
+	if g.rand.Intn(2) == 0 {
+		n = -n
+	}
+	return n
+}
+
+func (g *Generator) list(typ string, depth, min, max int) []any {
+	values := make([]any, min+g.rand.Intn(max-min+1))
+	for i := range values {
+		values[i] = g.expr(typ, depth)
+	}
+	return values
+}
+
+func (g *Generator) iteration(body string, depth int) []any {
+	array := g.expr("array", depth)
+
+	outer := g.body
+	g.body = true
+	defer func() { g.body = outer }()
+
+	rule := g.expr(body, depth)
+	if _, ok := rule.(map[string]any); !ok {
+		// the bodies must be operations
+		rule = map[string]any{wrappers[body]: []any{rule}}
+	}
+
+	return []any{array, rule}
+}
+
+// wrappers are the operations keeping a literal of their type as it is.
+var wrappers = map[string]string{
+	"number":  "+",
+	"boolean": "!!",
+}
+
+// builtin describes how to generate the arguments of a built-in operator.
+type builtin struct {
+	// result is the type produced, or "" when the operator produces the
+	// type asked for.
+	result string
+	args   func(g *Generator, typ string, depth int) []any
+}
+
+func numbers(min, max int) func(g *Generator, typ string, depth int) []any {
+	return func(g *Generator, _ string, depth int) []any {
+		return g.list("number", depth, min, max)
+	}
+}
+
+func division(g *Generator, _ string, depth int) []any {
+	return []any{g.expr("number", depth), g.nonZero()}
+}
+
+func comparison(g *Generator, _ string, depth int) []any {
+	typ := []string{"number", "string", "boolean"}[g.rand.Intn(3)]
+	return []any{g.expr(typ, depth), g.expr(typ, depth)}
+}
+
+func iterator(body string) func(g *Generator, typ string, depth int) []any {
+	return func(g *Generator, _ string, depth int) []any {
+		return g.iteration(body, depth)
+	}
+}
+
+// builtins is filled by init, as its functions generate nested operations from it.
+var builtins map[string]builtin
+
+func init() {
+	builtins = map[string]builtin{
+		"+":   {result: "number", args: numbers(1, 3)},
+		"-":   {result: "number", args: numbers(1, 2)},
+		"*":   {result: "number", args: numbers(1, 3)},
+		"/":   {result: "number", args: division},
+		"%":   {result: "number", args: division},
+		"min": {result: "number", args: numbers(1, 3)},
+		"max": {result: "number", args: numbers(1, 3)},
+		"abs": {result: "number", args: numbers(1, 1)},
+		"cat": {result: "string", args: func(g *Generator, _ string, depth int) []any {
+			return g.list("string", depth, 1, 3)
+		}},
+		"substr": {result: "string", args: func(g *Generator, _ string, depth int) []any {
+			return []any{g.expr("string", depth), float64(g.rand.Intn(7) - 3)}
+		}},
+		"==":  {result: "boolean", args: comparison},
+		"!=":  {result: "boolean", args: comparison},
+		"===": {result: "boolean", args: comparison},
+		"!==": {result: "boolean", args: comparison},
+		"<":   {result: "boolean", args: numbers(2, 2)},
+		"<=":  {result: "boolean", args: numbers(2, 2)},
+		">":   {result: "boolean", args: numbers(2, 2)},
+		">=":  {result: "boolean", args: numbers(2, 2)},
+		"!": {result: "boolean", args: func(g *Generator, _ string, depth int) []any {
+			return []any{g.expr("any", depth)}
+		}},
+		"!!": {result: "boolean", args: func(g *Generator, _ string, depth int) []any {
+			return []any{g.expr("any", depth)}
+		}},
+		"and": {result: "boolean", args: func(g *Generator, _ string, depth int) []any {
+			return g.list("boolean", depth, 1, 3)
+		}},
+		"or": {result: "boolean", args: func(g *Generator, _ string, depth int) []any {
+			return g.list("boolean", depth, 1, 3)
+		}},
+		"in": {result: "boolean", args: func(g *Generator, _ string, depth int) []any {
+			if g.rand.Intn(2) == 0 {
+				return []any{g.expr("string", depth), g.expr("string", depth)}
+			}
+			return []any{g.expr("number", depth), g.expr("array", depth)}
+		}},
+		"if": {result: "", args: func(g *Generator, typ string, depth int) []any {
+			return []any{g.expr("boolean", depth), g.expr(typ, depth), g.expr(typ, depth)}
+		}},
+		"merge": {result: "array", args: func(g *Generator, _ string, depth int) []any {
+			return g.list("array", depth, 1, 2)
+		}},
+		"map":    {result: "array", args: iterator("number")},
+		"filter": {result: "array", args: iterator("boolean")},
+		"some":   {result: "boolean", args: iterator("boolean")},
+		"all":    {result: "boolean", args: iterator("boolean")},
+		"none":   {result: "boolean", args: iterator("boolean")},
+	}
+}
+
+// set stores the value at the path, creating the objects on the way.
+func set(data map[string]any, path jsonlogic.Path, value any) {
+	for _, key := range path[:len(path)-1] {
+		next, ok := data[key].(map[string]any)
+		if !ok {
+			next = make(map[string]any)
+			data[key] = next
+		}
+		data = next
+	}
+	data[path[len(path)-1]] = value
+}
+
+// ShrinkRule returns rules smaller than the given one, simplest first: the
+// arguments of its operations taken out of them, operations with fewer
+// arguments and simpler literals. The candidates may not be well typed.
+func ShrinkRule(rule any) []any {
+	switch v := rule.(type) {
+	case map[string]any:
+		if len(v) != 1 {
+			return shrinkValue(v)
+		}
+
+		var operator string
+		for key := range v {
+			operator = key
+		}
+		if operator == "var" || operator == "val" {
+			return nil
+		}
+
+		args, ok := v[operator].([]any)
+		if !ok {
+			args = []any{v[operator]}
+		}
+
+		var candidates []any
+		candidates = append(candidates, args...)
+		if len(args) > 1 {
+			for i := range args {
+				candidates = append(candidates, map[string]any{operator: without(args, i)})
+			}
+		}
+		for i, arg := range args {
+			for _, smaller := range ShrinkRule(arg) {
+				candidates = append(candidates, map[string]any{operator: replaced(args, i, smaller)})
+			}
+		}
+		return candidates
+	case []any:
+		var candidates []any
+		for i := range v {
+			candidates = append(candidates, without(v, i))
+		}
+		for i, value := range v {
+			for _, smaller := range ShrinkRule(value) {
+				candidates = append(candidates, replaced(v, i, smaller))
+			}
+		}
+		return candidates
+	}
+
+	return shrinkValue(rule)
+}
+
+// ShrinkData returns documents smaller than the given one, simplest first:
+// with fewer keys or elements and with simpler values.
+func ShrinkData(data any) []any {
+	return shrinkValue(data)
+}
+
+func shrinkValue(value any) []any {
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var candidates []any
+		for _, key := range keys {
+			smaller := copyMap(v)
+			delete(smaller, key)
+			candidates = append(candidates, smaller)
+		}
+		for _, key := range keys {
+			for _, s := range shrinkValue(v[key]) {
+				smaller := copyMap(v)
+				smaller[key] = s
+				candidates = append(candidates, smaller)
+			}
+		}
+		return candidates
+	case []any:
+		var candidates []any
+		for i := range v {
+			candidates = append(candidates, without(v, i))
+		}
+		for i, element := range v {
+			for _, s := range shrinkValue(element) {
+				candidates = append(candidates, replaced(v, i, s))
+			}
+		}
+		return candidates
+	case float64:
+		var candidates []any
+		for _, n := range []float64{0, math.Trunc(v), math.Trunc(v / 2)} {
+			if n != v && !contains(candidates, n) {
+				candidates = append(candidates, n)
+			}
+		}
+		return candidates
+	case string:
+		if v == "" {
+			return nil
+		}
+		candidates := []any{""}
+		if runes := []rune(v); len(runes) > 1 {
+			candidates = append(candidates, string(runes[:len(runes)/2]))
+		}
+		return candidates
+	case bool:
+		if v {
+			return []any{false}
+		}
+	}
+
+	return nil
+}
+
+// maxShrinks bounds the candidates tried by Minimize.
+const maxShrinks = 10000
+
+// Minimize shrinks the rule and then the data of a case for which fails
+// returns true, for as long as the smaller case keeps failing, and returns
+// the smallest failing case found.
+func Minimize(c RandomCase, fails func(RandomCase) bool) RandomCase {
+	tries := 0
+
+	for shrunk := true; shrunk && tries < maxShrinks; {
+		shrunk = false
+
+		for _, rule := range ShrinkRule(c.Rule) {
+			tries++
+			if candidate := (RandomCase{Rule: rule, Data: c.Data}); fails(candidate) {
+				c, shrunk = candidate, true
+				break
+			}
+		}
+		if shrunk {
+			continue
+		}
+
+		for _, data := range ShrinkData(c.Data) {
+			tries++
+			if candidate := (RandomCase{Rule: c.Rule, Data: data}); fails(candidate) {
+				c, shrunk = candidate, true
+				break
+			}
+		}
+	}
+
+	return c
+}
+
+func without(values []any, i int) []any {
+	result := make([]any, 0, len(values)-1)
+	result = append(result, values[:i]...)
+	return append(result, values[i+1:]...)
+}
+
+func replaced(values []any, i int, value any) []any {
+	result := append([]any(nil), values...)
+	result[i] = value
+	return result
+}
+
+func copyMap(m map[string]any) map[string]any {
+	result := make(map[string]any, len(m))
+	for key, value := range m {
+		result[key] = value
+	}
+	return result
+}
+
+func contains(values []any, value any) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
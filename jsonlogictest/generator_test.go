@@ -0,0 +1,173 @@
+package jsonlogictest_test
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/jsonlogictest"
+)
+
+func TestGeneratorIsDeterministic(t *testing.T) {
+	a := jsonlogictest.NewGenerator(42)
+	b := jsonlogictest.NewGenerator(42)
+
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, a.Case(), b.Case())
+	}
+}
+
+func TestGeneratedRulesAreValid(t *testing.T) {
+	for seed := int64(0); seed < 500; seed++ {
+		c := jsonlogictest.NewGenerator(seed).Case()
+
+		assert.True(t, jsonlogic.ValidateJsonLogic(c.Rule), "seed %d: %#v", seed, c)
+
+		_, err := jsonlogic.ApplyInterface(c.Rule, c.Data)
+		assert.NoError(t, err, "seed %d: %#v", seed, c)
+	}
+}
+
+func TestGeneratedDataSatisfiesTheVariables(t *testing.T) {
+	for seed := int64(0); seed < 200; seed++ {
+		c := jsonlogictest.NewGenerator(seed).Case()
+
+		for _, path := range jsonlogic.Variables(c.Rule) {
+			_, err := jsonlogic.ApplyInterfaceWithOptions(map[string]any{"var": path.String()}, c.Data, jsonlogic.Options{Strict: true})
+			assert.NoError(t, err, "seed %d: %#v", seed, c)
+		}
+	}
+}
+
+func TestGeneratorRuleOfType(t *testing.T) {
+	g := jsonlogictest.NewGenerator(7)
+
+	for i := 0; i < 50; i++ {
+		rule := g.RuleOfType("boolean")
+
+		result, err := jsonlogic.ApplyInterface(rule, g.Data(rule))
+		assert.NoError(t, err)
+		assert.IsType(t, true, result, "%s", toJSON(rule))
+	}
+}
+
+func TestGeneratorUsesTypedOperators(t *testing.T) {
+	jsonlogic.RegisterFunc2("generated_repeat", func(s string, n int) (string, error) {
+		if n < 0 {
+			n = -n
+		}
+		return strings.Repeat(s, n), nil
+	})
+
+	g := jsonlogictest.NewGenerator(1)
+	g.Operators = []string{"generated_repeat", "cat"}
+
+	used := false
+	for i := 0; i < 50; i++ {
+		rule := g.RuleOfType("string")
+		used = used || strings.Contains(toJSON(rule), "generated_repeat")
+
+		_, err := jsonlogic.ApplyInterface(rule, g.Data(rule))
+		assert.NoError(t, err, "%s", toJSON(rule))
+	}
+	assert.True(t, used)
+}
+
+func TestRandomCaseWithQuick(t *testing.T) {
+	// evaluating a rule doesn't depend on it having been through JSON
+	property := func(c jsonlogictest.RandomCase) bool {
+		return sameResult(c)
+	}
+
+	assert.NoError(t, quick.Check(property, &quick.Config{MaxCount: 200}))
+}
+
+func TestMinimize(t *testing.T) {
+	c := jsonlogictest.RandomCase{
+		Rule: map[string]any{"and": []any{
+			map[string]any{"<": []any{map[string]any{"var": "v1"}, float64(3)}},
+			map[string]any{"==": []any{
+				map[string]any{"%": []any{map[string]any{"var": "v2"}, float64(4)}},
+				float64(1.5),
+			}},
+		}},
+		Data: map[string]any{"v1": float64(2), "v2": float64(-7.5)},
+	}
+
+	minimized := jsonlogictest.Minimize(c, func(c jsonlogictest.RandomCase) bool {
+		return strings.Contains(toJSON(c.Rule), `"%"`)
+	})
+
+	assert.Equal(t, map[string]any{"%": []any{float64(0)}}, minimized.Rule)
+	assert.Equal(t, map[string]any{}, minimized.Data)
+}
+
+func TestShrinkRule(t *testing.T) {
+	candidates := jsonlogictest.ShrinkRule(map[string]any{"+": []any{float64(2), map[string]any{"var": "a"}}})
+
+	assert.Equal(t, []any{
+		float64(2),
+		map[string]any{"var": "a"},
+		map[string]any{"+": []any{map[string]any{"var": "a"}}},
+		map[string]any{"+": []any{float64(2)}},
+		map[string]any{"+": []any{float64(0), map[string]any{"var": "a"}}},
+		map[string]any{"+": []any{float64(1), map[string]any{"var": "a"}}},
+	}, candidates)
+
+	assert.Empty(t, jsonlogictest.ShrinkRule(map[string]any{"var": "a"}))
+}
+
+func TestShrinkData(t *testing.T) {
+	candidates := jsonlogictest.ShrinkData(map[string]any{"a": true, "b": "xy"})
+
+	assert.Equal(t, []any{
+		map[string]any{"b": "xy"},
+		map[string]any{"a": true},
+		map[string]any{"a": false, "b": "xy"},
+		map[string]any{"a": true, "b": ""},
+		map[string]any{"a": true, "b": "x"},
+	}, candidates)
+}
+
+func FuzzGeneratedRules(f *testing.F) {
+	for seed := int64(0); seed < 20; seed++ {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, seed int64) {
+		c := jsonlogictest.NewGenerator(seed).Case()
+		if !sameResult(c) {
+			t.Errorf("%#v evaluates differently once encoded", c)
+		}
+	})
+}
+
+func sameResult(c jsonlogictest.RandomCase) bool {
+	expected, err := jsonlogic.ApplyInterface(c.Rule, c.Data)
+	if err != nil {
+		return false
+	}
+
+	rule, _ := json.Marshal(c.Rule)
+	data, _ := json.Marshal(c.Data)
+
+	got, err := jsonlogic.ApplyRaw(rule, data)
+	if err != nil {
+		return false
+	}
+
+	return len(jsonlogictest.Diff(expected, json.RawMessage(got))) == 0
+}
+
+func toJSON(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		panic(errors.New("not JSON"))
+	}
+	return string(encoded)
+}
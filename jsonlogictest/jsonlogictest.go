@@ -0,0 +1,340 @@
+// Package jsonlogictest runs conformance suites against JsonLogic evaluators,
+// and generates random rules and data for property-based tests.
+//
+// A suite is a JSON array in the format of the official tests.json: every
+// case is an array holding the rule, the data and the expected result, and
+// the strings between the cases name the section of the cases after them.
+//
+//	[
+//	  "# Comparison",
+//	  [ {"==": [1, 1]}, {}, true ],
+//	  [ {"<": [{"var": "a"}, 2]}, {"a": 1}, true ]
+//	]
+//
+// The package holds copies of the suite published at
+// https://jsonlogic.com/tests.json, refreshed with go generate, and of the
+// community suite, proposed as the new official one in
+// https://github.com/jwadhams/json-logic/pull/48. Custom operators and engine
+// configurations can be checked against fixture files in the same format:
+//
+//	suite, err := jsonlogictest.LoadFile("testdata/operators.json")
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	jsonlogictest.Test(t, suite, jsonlogic.ApplyInterface)
+//
+// Test files, described in LoadTestFile, describe the tests of rules in YAML
+// or JSON, with expected errors, tags and table-driven cases. TestFiles runs
+// them from go test, and the test command of cmd/jsonlogic from the shell.
+package jsonlogictest
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+)
+
+//go:generate curl -sSfL -o suites/official.json https://jsonlogic.com/tests.json
+
+//go:embed suites/*.json
+var suites embed.FS
+
+// EvalFunc evaluates a rule against the data. jsonlogic.ApplyInterface and
+// the ApplyInterface method of jsonlogic.Engine are EvalFuncs.
+type EvalFunc func(rule, data any) (any, error)
+
+// Case is a rule applied to data, with the result it must produce.
+type Case struct {
+	Section  string
+	Index    int
+	Rule     any
+	Data     any
+	Expected any
+
+	// Error, when not empty, makes the case expect the evaluation to fail
+	// with an error containing it instead of producing a result.
+	Error string
+
+	// Tags come from test files, and are empty in the suites in the format
+	// of tests.json.
+	Tags []string
+}
+
+// Name identifies the case in its suite, as the section and the position of
+// the case in the section.
+func (c Case) Name() string {
+	return fmt.Sprintf("%s#%d", c.Section, c.Index)
+}
+
+// Suite is a named list of cases.
+type Suite struct {
+	Name  string
+	Cases []Case
+}
+
+// Official returns the suite published at https://jsonlogic.com/tests.json.
+func Official() (Suite, error) {
+	return embedded("official")
+}
+
+// Community returns the suite proposed as the new official one in
+// https://github.com/jwadhams/json-logic/pull/48.
+func Community() (Suite, error) {
+	return embedded("community")
+}
+
+func embedded(name string) (Suite, error) {
+	f, err := suites.Open("suites/" + name + ".json")
+	if err != nil {
+		return Suite{}, err
+	}
+	defer f.Close()
+
+	return Parse(name, f)
+}
+
+// LoadFile reads a suite from a file, named after the file without its extension.
+func LoadFile(path string) (Suite, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Suite{}, err
+	}
+	defer f.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	return Parse(name, f)
+}
+
+// Parse reads a suite in the format of the official tests.json.
+func Parse(name string, r io.Reader) (Suite, error) {
+	var entries []json.RawMessage
+
+	if err := json.NewDecoder(r).Decode(&entries); err != nil {
+		return Suite{}, fmt.Errorf("jsonlogictest: suite %s: %w", name, err)
+	}
+
+	suite := Suite{Name: name}
+
+	section := ""
+	index := 0
+
+	for i, entry := range entries {
+		var comment string
+		if err := json.Unmarshal(entry, &comment); err == nil {
+			section = strings.TrimSpace(strings.TrimLeft(comment, "#"))
+			index = 0
+			continue
+		}
+
+		var values []any
+		if err := json.Unmarshal(entry, &values); err != nil || len(values) != 3 {
+			return Suite{}, fmt.Errorf("jsonlogictest: suite %s: entry %d must be a string or an array with the rule, the data and the expected result", name, i)
+		}
+
+		suite.Cases = append(suite.Cases, Case{
+			Section:  section,
+			Index:    index,
+			Rule:     values[0],
+			Data:     values[1],
+			Expected: values[2],
+		})
+		index++
+	}
+
+	return suite, nil
+}
+
+// Failure is a case whose evaluation failed or didn't produce the expected result.
+type Failure struct {
+	Case Case
+	Got  any
+	Err  error
+
+	// Diff lists the differences between the expected result and the one
+	// produced, one per line, prefixed by their location as a JSON Pointer.
+	Diff string
+}
+
+func (f Failure) String() string {
+	if f.Err != nil && f.Case.Error == "" {
+		return fmt.Sprintf("%s: applying %s to %s failed: %s", f.Case.Name(), toJSON(f.Case.Rule), toJSON(f.Case.Data), f.Err)
+	}
+
+	return fmt.Sprintf("%s: applying %s to %s\n%s", f.Case.Name(), toJSON(f.Case.Rule), toJSON(f.Case.Data), f.Diff)
+}
+
+// Report is the outcome of running a suite.
+type Report struct {
+	Suite    string
+	Passed   int
+	Failures []Failure
+}
+
+// OK reports whether every case passed.
+func (r Report) OK() bool {
+	return len(r.Failures) == 0
+}
+
+func (r Report) String() string {
+	var s strings.Builder
+
+	fmt.Fprintf(&s, "%s: %d passed, %d failed", r.Suite, r.Passed, len(r.Failures))
+	for _, failure := range r.Failures {
+		s.WriteString("\n")
+		s.WriteString(failure.String())
+	}
+
+	return s.String()
+}
+
+// Run evaluates every case of the suite and reports the ones failing.
+func Run(suite Suite, eval EvalFunc) Report {
+	report := Report{Suite: suite.Name}
+
+	for _, c := range suite.Cases {
+		if failure, failed := check(c, eval); failed {
+			report.Failures = append(report.Failures, failure)
+		} else {
+			report.Passed++
+		}
+	}
+
+	return report
+}
+
+// Test runs every case of the suite as a subtest of t.
+func Test(t *testing.T, suite Suite, eval EvalFunc) {
+	t.Helper()
+
+	for _, c := range suite.Cases {
+		c := c
+		t.Run(c.Name(), func(t *testing.T) {
+			if failure, failed := check(c, eval); failed {
+				t.Error(failure.String())
+			}
+		})
+	}
+}
+
+func check(c Case, eval EvalFunc) (Failure, bool) {
+	got, err := eval(c.Rule, c.Data)
+	if c.Error != "" {
+		switch {
+		case err == nil:
+			return Failure{Case: c, Got: got, Diff: fmt.Sprintf("(root): expected an error containing %q, got %s", c.Error, toJSON(got))}, true
+		case !strings.Contains(err.Error(), c.Error):
+			return Failure{Case: c, Err: err, Diff: fmt.Sprintf("(root): expected an error containing %q, got %q", c.Error, err.Error())}, true
+		}
+		return Failure{}, false
+	}
+	if err != nil {
+		return Failure{Case: c, Err: err}, true
+	}
+
+	differences := Diff(c.Expected, got)
+	if len(differences) == 0 {
+		return Failure{}, false
+	}
+
+	return Failure{Case: c, Got: got, Diff: strings.Join(differences, "\n")}, true
+}
+
+// Diff compares the expected and the produced values as JSON, so numbers of
+// any Go type holding the same value are equal. It returns one line per
+// difference, prefixed by its location as a JSON Pointer, or by "(root)" when
+// the values themselves differ.
+func Diff(expected, got any) []string {
+	var differences []string
+	diff("", normalize(expected), normalize(got), &differences)
+	return differences
+}
+
+func diff(pointer string, expected, got any, differences *[]string) {
+	location := pointer
+	if location == "" {
+		location = "(root)"
+	}
+
+	switch e := expected.(type) {
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			break
+		}
+		if len(e) != len(g) {
+			*differences = append(*differences, fmt.Sprintf("%s: expected %d elements, got %d: expected %s, got %s", location, len(e), len(g), toJSON(e), toJSON(g)))
+			return
+		}
+		for i := range e {
+			diff(fmt.Sprintf("%s/%d", pointer, i), e[i], g[i], differences)
+		}
+		return
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			break
+		}
+		for _, key := range sortedKeys(e, g) {
+			escaped := strings.NewReplacer("~", "~0", "/", "~1").Replace(key)
+			ev, inExpected := e[key]
+			gv, inGot := g[key]
+			switch {
+			case !inGot:
+				*differences = append(*differences, fmt.Sprintf("%s/%s: missing, expected %s", pointer, escaped, toJSON(ev)))
+			case !inExpected:
+				*differences = append(*differences, fmt.Sprintf("%s/%s: unexpected %s", pointer, escaped, toJSON(gv)))
+			default:
+				diff(pointer+"/"+escaped, ev, gv, differences)
+			}
+		}
+		return
+	}
+
+	if toJSON(expected) != toJSON(got) {
+		*differences = append(*differences, fmt.Sprintf("%s: expected %s, got %s", location, toJSON(expected), toJSON(got)))
+	}
+}
+
+// normalize turns the value into the types produced by encoding/json.
+func normalize(value any) any {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	var normalized any
+	if err := json.Unmarshal(encoded, &normalized); err != nil {
+		return value
+	}
+
+	return normalized
+}
+
+func toJSON(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%#v", value)
+	}
+	return string(encoded)
+}
+
+func sortedKeys(a, b map[string]any) []string {
+	keys := make([]string, 0, len(a)+len(b))
+	for key := range a {
+		keys = append(keys, key)
+	}
+	for key := range b {
+		if _, ok := a[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,116 @@
+package jsonlogictest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/jsonlogictest"
+)
+
+func TestParse(t *testing.T) {
+	suite, err := jsonlogictest.Parse("example", strings.NewReader(`[
+		"# Comparison",
+		[{"==": [1, 1]}, {}, true],
+		[{"<": [{"var": "a"}, 2]}, {"a": 1}, true],
+		"Strings",
+		[{"cat": ["a", "b"]}, null, "ab"]
+	]`))
+	assert.NoError(t, err)
+	assert.Equal(t, "example", suite.Name)
+
+	var names []string
+	for _, c := range suite.Cases {
+		names = append(names, c.Name())
+	}
+	assert.Equal(t, []string{"Comparison#0", "Comparison#1", "Strings#0"}, names)
+	assert.Equal(t, map[string]any{"a": float64(1)}, suite.Cases[1].Data)
+	assert.Equal(t, "ab", suite.Cases[2].Expected)
+
+	_, err = jsonlogictest.Parse("invalid", strings.NewReader(`[[{"==": [1, 1]}, {}]]`))
+	assert.EqualError(t, err, "jsonlogictest: suite invalid: entry 0 must be a string or an array with the rule, the data and the expected result")
+
+	_, err = jsonlogictest.Parse("invalid", strings.NewReader(`{`))
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	suite, err := jsonlogictest.Parse("example", strings.NewReader(`[
+		"# Passing",
+		[{"+": [1, 2]}, {}, 3],
+		"# Failing",
+		[{"map": [[1, 2], {"*": [{"var": ""}, 2]}]}, {}, [2, 5]],
+		[{"unknown": [1]}, {}, 1]
+	]`))
+	assert.NoError(t, err)
+
+	report := jsonlogictest.Run(suite, jsonlogic.ApplyInterface)
+	assert.False(t, report.OK())
+	assert.Equal(t, 1, report.Passed)
+
+	if assert.Len(t, report.Failures, 2) {
+		assert.Equal(t, "Failing#0", report.Failures[0].Case.Name())
+		assert.Equal(t, "/1: expected 5, got 4", report.Failures[0].Diff)
+		assert.Equal(t, []any{float64(2), float64(4)}, report.Failures[0].Got)
+
+		assert.Equal(t, "Failing#1", report.Failures[1].Case.Name())
+		assert.Error(t, report.Failures[1].Err)
+	}
+
+	assert.Equal(t, `example: 1 passed, 2 failed
+Failing#0: applying {"map":[[1,2],{"*":[{"var":""},2]}]} to {}
+/1: expected 5, got 4
+Failing#1: applying {"unknown":[1]} to {} failed: The operator "unknown" is not supported`, report.String())
+}
+
+func TestDiff(t *testing.T) {
+	scenarios := map[string]struct {
+		expected any
+		got      any
+		diff     []string
+	}{
+		"equal":              {expected: 1, got: float64(1)},
+		"ints and floats":    {expected: []any{1, 2.5}, got: []any{float64(1), 2.5}},
+		"different values":   {expected: "a", got: "b", diff: []string{`(root): expected "a", got "b"`}},
+		"different types":    {expected: 1, got: "1", diff: []string{`(root): expected 1, got "1"`}},
+		"different lengths":  {expected: []any{1}, got: []any{1, 2}, diff: []string{`(root): expected 1 elements, got 2: expected [1], got [1,2]`}},
+		"nested":             {expected: map[string]any{"a": []any{1, map[string]any{"b/c": true}}}, got: map[string]any{"a": []any{1, map[string]any{"b/c": false}}}, diff: []string{`/a/1/b~1c: expected true, got false`}},
+		"missing and extras": {expected: map[string]any{"a": 1}, got: map[string]any{"b": 1}, diff: []string{`/a: missing, expected 1`, `/b: unexpected 1`}},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, scenario.diff, jsonlogictest.Diff(scenario.expected, scenario.got))
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "custom_operators.json")
+	assert.NoError(t, os.WriteFile(path, []byte(`["# Custom", [{"+": [1, 1]}, {}, 2]]`), 0o600))
+
+	suite, err := jsonlogictest.LoadFile(path)
+	assert.NoError(t, err)
+	assert.Equal(t, "custom_operators", suite.Name)
+	assert.Len(t, suite.Cases, 1)
+
+	jsonlogictest.Test(t, suite, jsonlogic.ApplyInterface)
+
+	_, err = jsonlogictest.LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
+
+func TestVendoredSuites(t *testing.T) {
+	community, err := jsonlogictest.Community()
+	assert.NoError(t, err)
+	assert.Equal(t, "community", community.Name)
+	assert.NotEmpty(t, community.Cases)
+
+	official, err := jsonlogictest.Official()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, official.Cases)
+}
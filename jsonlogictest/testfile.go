@@ -0,0 +1,313 @@
+package jsonlogictest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// testFile is the content of a test file, described in LoadTestFile.
+type testFile struct {
+	Rules map[string]any `json:"rules"`
+	Tests []testEntry    `json:"tests"`
+}
+
+type testEntry struct {
+	testVariant
+	Description string          `json:"description"`
+	Rule        json.RawMessage `json:"rule"`
+	Ref         string          `json:"ref"`
+	Cases       []testVariant   `json:"cases"`
+}
+
+type testVariant struct {
+	Data     json.RawMessage `json:"data"`
+	Expected json.RawMessage `json:"expected"`
+	Error    string          `json:"error"`
+	Tags     []string        `json:"tags"`
+}
+
+// LoadTestFile reads a test file, or a suite in the format of the official
+// tests.json, named after the file without its extension. The rules
+// referenced by path are read relative to the directory of the file, and may
+// be outside of it, like in ../rules/adult.json.
+//
+// A test file keeps the tests of rules next to them, in YAML or in JSON:
+//
+//	rules:
+//	  adult: {">=": [{"var": "age"}, 18]}
+//
+//	tests:
+//	  - description: adults are allowed
+//	    ref: adult
+//	    data: {age: 21}
+//	    expected: true
+//	    tags: [smoke]
+//
+//	  - description: the age limit
+//	    ref: rules/adult.json
+//	    cases:
+//	      - {data: {age: 17}, expected: false}
+//	      - {data: {age: 18}, expected: true}
+//
+//	  - description: unknown operators fail
+//	    rule: {"unknown": [1]}
+//	    error: not supported
+//
+// Every test holds its rule inline in rule, or references it in ref by the
+// name of one of the rules of the file or by the path of a file holding it,
+// relative to the test file. A test expects either a result in expected or
+// the evaluation to fail with an error containing error. The cases of a
+// table-driven test inherit the rule, the data, the expectation and the tags
+// of the test, and override them.
+func LoadTestFile(path string) (Suite, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Suite{}, err
+	}
+	defer f.Close()
+
+	name := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	return ParseTestFile(name, f, relativeDir(filepath.Dir(path)))
+}
+
+// relativeDir opens the files of the paths relative to a directory, including
+// the ones going out of it with "..", which os.DirFS rejects.
+type relativeDir string
+
+func (dir relativeDir) Open(name string) (fs.File, error) {
+	return os.Open(filepath.Join(string(dir), filepath.FromSlash(name)))
+}
+
+// ParseTestFile reads a test file, or a suite in the format of the official
+// tests.json. The rules referenced by path are read from dir, which may be
+// nil when the tests don't reference any file. The paths are given to dir as
+// they're written, so an fs.FS like the ones of os.DirFS and embed rejects
+// the ones starting with "..".
+func ParseTestFile(name string, r io.Reader, dir fs.FS) (Suite, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return Suite{}, fmt.Errorf("jsonlogictest: tests %s: %w", name, err)
+	}
+
+	encoded, err := decodeYAML(content)
+	if err != nil {
+		return Suite{}, fmt.Errorf("jsonlogictest: tests %s: %w", name, err)
+	}
+
+	if bytes.HasPrefix(encoded, []byte("[")) {
+		return Parse(name, bytes.NewReader(encoded))
+	}
+
+	var file testFile
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return Suite{}, fmt.Errorf("jsonlogictest: tests %s: %w", name, err)
+	}
+
+	suite := Suite{Name: name}
+	indexes := make(map[string]int)
+
+	for i, entry := range file.Tests {
+		cases, err := entry.expand(file.Rules, dir)
+		if err != nil {
+			return Suite{}, fmt.Errorf("jsonlogictest: tests %s: test %d: %w", name, i, err)
+		}
+
+		section := entry.Description
+		if section == "" {
+			section = fmt.Sprintf("test %d", i)
+		}
+
+		for _, c := range cases {
+			c.Section = section
+			c.Index = indexes[section]
+			indexes[section]++
+			suite.Cases = append(suite.Cases, c)
+		}
+	}
+
+	return suite, nil
+}
+
+// decodeYAML reads a YAML document, which may be JSON, and encodes it as JSON.
+func decodeYAML(content []byte) ([]byte, error) {
+	var document any
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(document)
+}
+
+func (entry testEntry) expand(rules map[string]any, dir fs.FS) ([]Case, error) {
+	rule, err := entry.rule(rules, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	variants := entry.Cases
+	if len(variants) == 0 {
+		variants = []testVariant{{}}
+	}
+
+	cases := make([]Case, 0, len(variants))
+	for i, variant := range variants {
+		c, err := entry.testVariant.override(variant).toCase(rule)
+		if err != nil {
+			if len(entry.Cases) > 0 {
+				return nil, fmt.Errorf("case %d: %w", i, err)
+			}
+			return nil, err
+		}
+		cases = append(cases, c)
+	}
+
+	return cases, nil
+}
+
+func (entry testEntry) rule(rules map[string]any, dir fs.FS) (any, error) {
+	switch {
+	case entry.Rule != nil && entry.Ref != "":
+		return nil, fmt.Errorf("rule and ref can't be used together")
+	case entry.Rule != nil:
+		var rule any
+		err := json.Unmarshal(entry.Rule, &rule)
+		return rule, err
+	case entry.Ref == "":
+		return nil, fmt.Errorf("rule or ref is required")
+	}
+
+	if rule, ok := rules[entry.Ref]; ok {
+		return rule, nil
+	}
+
+	if dir == nil {
+		return nil, fmt.Errorf("the rule %q is not defined", entry.Ref)
+	}
+
+	content, err := fs.ReadFile(dir, filepath.ToSlash(entry.Ref))
+	if err != nil {
+		return nil, fmt.Errorf("the rule %q is not defined: %w", entry.Ref, err)
+	}
+
+	encoded, err := decodeYAML(content)
+	if err != nil {
+		return nil, fmt.Errorf("the rule %q: %w", entry.Ref, err)
+	}
+
+	var rule any
+	err = json.Unmarshal(encoded, &rule)
+	return rule, err
+}
+
+// override returns the test with the fields set in the variant replaced.
+func (t testVariant) override(variant testVariant) testVariant {
+	if variant.Data != nil {
+		t.Data = variant.Data
+	}
+	if variant.Expected != nil || variant.Error != "" {
+		t.Expected, t.Error = variant.Expected, variant.Error
+	}
+	t.Tags = append(append([]string(nil), t.Tags...), variant.Tags...)
+
+	return t
+}
+
+func (t testVariant) toCase(rule any) (Case, error) {
+	switch {
+	case t.Expected != nil && t.Error != "":
+		return Case{}, fmt.Errorf("expected and error can't be used together")
+	case t.Expected == nil && t.Error == "":
+		return Case{}, fmt.Errorf("expected or error is required")
+	}
+
+	c := Case{Rule: rule, Error: t.Error, Tags: t.Tags}
+
+	if t.Data != nil {
+		if err := json.Unmarshal(t.Data, &c.Data); err != nil {
+			return Case{}, err
+		}
+	}
+	if t.Expected != nil {
+		if err := json.Unmarshal(t.Expected, &c.Expected); err != nil {
+			return Case{}, err
+		}
+	}
+
+	return c, nil
+}
+
+// Filter returns the cases of the suite having at least one of the tags, or
+// the whole suite when no tag is given.
+func (s Suite) Filter(tags ...string) Suite {
+	if len(tags) == 0 {
+		return s
+	}
+
+	filtered := Suite{Name: s.Name}
+	for _, c := range s.Cases {
+		if hasAnyTag(c.Tags, tags) {
+			filtered.Cases = append(filtered.Cases, c)
+		}
+	}
+
+	return filtered
+}
+
+func hasAnyTag(tags, wanted []string) bool {
+	for _, tag := range tags {
+		for _, w := range wanted {
+			if tag == w {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// TestFiles runs the cases of the test files matching the glob patterns as
+// subtests of t, grouped by file:
+//
+//	func TestRules(t *testing.T) {
+//		jsonlogictest.TestFiles(t, jsonlogic.ApplyInterface, "rules/*_test.yaml")
+//	}
+func TestFiles(t *testing.T, eval EvalFunc, patterns ...string) {
+	t.Helper()
+
+	var paths []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, matches...)
+	}
+	sort.Strings(paths)
+
+	if len(paths) == 0 {
+		t.Fatalf("jsonlogictest: no test file matches %s", strings.Join(patterns, ", "))
+	}
+
+	for _, path := range paths {
+		suite, err := LoadTestFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		t.Run(suite.Name, func(t *testing.T) {
+			Test(t, suite, eval)
+		})
+	}
+}
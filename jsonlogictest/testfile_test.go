@@ -0,0 +1,116 @@
+package jsonlogictest_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/jsonlogictest"
+)
+
+func TestLoadTestFile(t *testing.T) {
+	suite, err := jsonlogictest.LoadTestFile("testdata/adult_test.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, "adult_test", suite.Name)
+
+	if assert.Len(t, suite.Cases, 4) {
+		assert.Equal(t, "adults#1", suite.Cases[1].Name())
+		assert.Equal(t, map[string]any{">=": []any{map[string]any{"var": "age"}, float64(18)}}, suite.Cases[1].Rule)
+		assert.Equal(t, map[string]any{"age": float64(18)}, suite.Cases[1].Data)
+		assert.Equal(t, true, suite.Cases[1].Expected)
+		assert.Equal(t, []string{"smoke"}, suite.Cases[1].Tags)
+
+		assert.Equal(t, "greeting#0", suite.Cases[2].Name())
+		assert.Equal(t, "Hello, Ana", suite.Cases[2].Expected)
+
+		assert.Equal(t, "not supported", suite.Cases[3].Error)
+	}
+
+	report := jsonlogictest.Run(suite, jsonlogic.ApplyInterface)
+	assert.True(t, report.OK(), report.String())
+	assert.Equal(t, 4, report.Passed)
+}
+
+func TestLoadTestFileWithRefsInAnotherFolder(t *testing.T) {
+	suite, err := jsonlogictest.LoadTestFile("testdata/suites/shared_test.yaml")
+	assert.NoError(t, err)
+
+	report := jsonlogictest.Run(suite, jsonlogic.ApplyInterface)
+	assert.True(t, report.OK(), report.String())
+	assert.Equal(t, 1, report.Passed)
+}
+
+func TestParseTestFile(t *testing.T) {
+	suite, err := jsonlogictest.ParseTestFile("example", strings.NewReader(`{
+		"tests": [
+			{"rule": {"var": "a"}, "data": {"a": [1, {"b": 2}]}, "expected": [1, {"b": 3}]},
+			{"rule": {"var": "a"}, "data": {"a": 1}, "error": "boom"},
+			{"rule": {"unknown": [1]}, "error": "boom"},
+			{"rule": {"var": "a"}, "expected": null, "tags": ["nulls"]}
+		]
+	}`), nil)
+	assert.NoError(t, err)
+
+	report := jsonlogictest.Run(suite, jsonlogic.ApplyInterface)
+	assert.Equal(t, 1, report.Passed)
+
+	if assert.Len(t, report.Failures, 3) {
+		assert.Equal(t, "test 0#0", report.Failures[0].Case.Name())
+		assert.Equal(t, "/1/b: expected 3, got 2", report.Failures[0].Diff)
+		assert.Equal(t, `(root): expected an error containing "boom", got 1`, report.Failures[1].Diff)
+		assert.Error(t, report.Failures[2].Err)
+		assert.Contains(t, report.Failures[2].String(), `test 2#0: applying {"unknown":[1]} to null
+(root): expected an error containing "boom", got `)
+	}
+
+	nulls := suite.Filter("nulls")
+	assert.Len(t, nulls.Cases, 1)
+	assert.Nil(t, nulls.Cases[0].Expected)
+	assert.Len(t, suite.Filter().Cases, 4)
+
+	tests, err := jsonlogictest.ParseTestFile("tests.json", strings.NewReader(`["# Section", [{"==": [1, 1]}, {}, true]]`), nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "Section#0", tests.Cases[0].Name())
+}
+
+func TestParseTestFileErrors(t *testing.T) {
+	scenarios := map[string]struct {
+		content string
+		err     string
+	}{
+		"rule and ref":         {content: `tests: [{rule: 1, ref: a, expected: 1}]`, err: "jsonlogictest: tests example: test 0: rule and ref can't be used together"},
+		"no rule":              {content: `tests: [{expected: 1}]`, err: "jsonlogictest: tests example: test 0: rule or ref is required"},
+		"undefined rule":       {content: `tests: [{ref: a, expected: 1}]`, err: `jsonlogictest: tests example: test 0: the rule "a" is not defined`},
+		"expected and error":   {content: `tests: [{rule: 1, expected: 1, error: boom}]`, err: "jsonlogictest: tests example: test 0: expected and error can't be used together"},
+		"no expectation":       {content: `tests: [{rule: 1, cases: [{expected: 1}, {data: {}}]}]`, err: "jsonlogictest: tests example: test 0: case 1: expected or error is required"},
+		"unknown field":        {content: `tests: [{rule: 1, expect: 1}]`, err: `jsonlogictest: tests example: json: unknown field "expect"`},
+		"invalid yaml":         {content: `tests: [`, err: "jsonlogictest: tests example: yaml: line 1: did not find expected node content"},
+		"invalid tests.json":   {content: `[[1]]`, err: "jsonlogictest: suite example: entry 0 must be a string or an array with the rule, the data and the expected result"},
+		"variants inherit all": {content: `tests: [{rule: 1, expected: 1, cases: [{data: 1}, {tags: [a]}]}]`},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			_, err := jsonlogictest.ParseTestFile("example", strings.NewReader(scenario.content), nil)
+			if scenario.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.EqualError(t, err, scenario.err)
+		})
+	}
+}
+
+func TestTestFiles(t *testing.T) {
+	jsonlogictest.TestFiles(t, jsonlogic.ApplyInterface, "testdata/*_test.yaml")
+
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rule.yaml"), []byte(`{"+": [{"var": "a"}, 1]}`), 0o600))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "rule_test.json"), []byte(`{"tests": [{"ref": "rule.yaml", "data": {"a": 1}, "expected": 2}]}`), 0o600))
+
+	jsonlogictest.TestFiles(t, jsonlogic.ApplyInterface, filepath.Join(dir, "*_test.json"))
+}
@@ -0,0 +1,126 @@
+package jsonlogic
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidLet represents an error when a "let" operation is malformed.
+// It contains the reason why the operation is invalid.
+type ErrInvalidLet struct {
+	reason string
+}
+
+func (e ErrInvalidLet) Error() string {
+	return fmt.Sprintf("The \"let\" operation is invalid: %s", e.reason)
+}
+
+// let evaluates the body with names bound to the results of expressions:
+//
+//	{"let": [{"total": {"+": [{"var": "price"}, {"var": "tax"}]}}, {">": [{"var": "total"}, 100]}]}
+//
+// Every expression is evaluated against the current data before any of the
+// names is bound, so they can't refer to each other; nest another "let" for
+// that. Inside the body, including the bodies of map, filter, reduce, all,
+// none and some, "var" and "val" read the bound names before the data, and
+// paths starting with a name read from its value, like {"var": "total.net"}.
+func (e *evaluator) let(values, data any) any {
+	bindings, body, err := letArguments(values)
+	if err != nil {
+		panic(err)
+	}
+
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	bound := make(map[string]any, len(bindings))
+	for _, name := range names {
+		bound[name] = e.parseValues(bindings[name], data)
+	}
+
+	defer e.bind(bound)()
+
+	return e.parseValues(body, data)
+}
+
+// letArguments splits the arguments of "let" into the bindings and the body.
+func letArguments(values any) (map[string]any, any, error) {
+	args, ok := values.([]any)
+	if !ok || len(args) != 2 {
+		return nil, nil, ErrInvalidLet{reason: "expected the bindings and the body"}
+	}
+
+	bindings, ok := args[0].(map[string]any)
+	if !ok {
+		return nil, nil, ErrInvalidLet{reason: "the bindings must be an object"}
+	}
+
+	for name := range bindings {
+		if !isBindingName(name) {
+			return nil, nil, ErrInvalidLet{reason: fmt.Sprintf("%q can't be bound", name)}
+		}
+	}
+
+	return bindings, args[1], nil
+}
+
+// isBindingName reports whether the name can be bound by "let". The name must
+// be reachable as the first segment of a dotted path.
+func isBindingName(name string) bool {
+	return name != "" && name != "*" && name != "**" &&
+		!strings.Contains(name, ".") && !strings.HasPrefix(name, "/")
+}
+
+// boundName returns the name a path starts with, if it can be a bound name.
+func boundName(path any) (string, bool) {
+	segments, ok := parsePath(path)
+	if !ok || len(segments) == 0 || segments[0].isIndex || !isBindingName(segments[0].key) {
+		return "", false
+	}
+
+	return segments[0].key, true
+}
+
+// boundVar resolves the variable, described by the already evaluated
+// arguments of "var", against the names bound by "let". It returns false when
+// the path doesn't start with a bound name.
+func (e *evaluator) boundVar(values any) (any, bool) {
+	if len(e.bindings) == 0 {
+		return nil, false
+	}
+
+	var _default any
+
+	path := values
+	if v, ok := values.([]any); ok {
+		if len(v) == 0 {
+			return nil, false
+		}
+		if len(v) == 2 {
+			_default = v[1]
+		}
+		path = v[0]
+	}
+
+	name, ok := boundName(path)
+	if !ok {
+		return nil, false
+	}
+
+	bound, ok := e.binding(name)
+	if !ok {
+		return nil, false
+	}
+
+	segments, _ := parsePath(path)
+	value, found := lookupPath(segments[1:], bound)
+	if !found {
+		return _default, true
+	}
+
+	return value, true
+}
@@ -0,0 +1,192 @@
+package jsonlogic_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestLet(t *testing.T) {
+	data := `{
+		"price": 100,
+		"tax": 20,
+		"total": 1,
+		"items": [{"price": 10}, {"price": 50}]
+	}`
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"names an expression": {
+			rule:     `{"let": [{"gross": {"+": [{"var": "price"}, {"var": "tax"}]}}, {"*": [{"var": "gross"}, 2]}]}`,
+			expected: `240`,
+		},
+		"binds many names": {
+			rule:     `{"let": [{"a": 1, "b": {"var": "tax"}}, {"+": [{"var": "a"}, {"var": "b"}]}]}`,
+			expected: `21`,
+		},
+		"shadows the data": {
+			rule:     `{"let": [{"total": 5}, {"var": "total"}]}`,
+			expected: `5`,
+		},
+		"binds null": {
+			rule:     `{"let": [{"total": null}, {"var": "total"}]}`,
+			expected: `null`,
+		},
+		"reads paths from the bound value": {
+			rule:     `{"let": [{"first": {"var": "items.0"}}, {"var": "first.price"}]}`,
+			expected: `10`,
+		},
+		"falls back to the default": {
+			rule:     `{"let": [{"first": {"var": "items.0"}}, {"var": ["first.discount", 0]}]}`,
+			expected: `0`,
+		},
+		"keeps other variables reading the data": {
+			rule:     `{"let": [{"gross": 120}, {"-": [{"var": "gross"}, {"var": "price"}]}]}`,
+			expected: `20`,
+		},
+		"expressions don't see each other": {
+			rule:     `{"let": [{"price": 1, "double": {"*": [{"var": "price"}, 2]}}, {"var": "double"}]}`,
+			expected: `200`,
+		},
+		"nested lets see the outer names": {
+			rule:     `{"let": [{"price": 1}, {"let": [{"double": {"*": [{"var": "price"}, 2]}}, {"var": "double"}]}]}`,
+			expected: `2`,
+		},
+		"inner names shadow outer ones": {
+			rule:     `{"let": [{"x": 1}, {"cat": [{"let": [{"x": 2}, {"var": "x"}]}, {"var": "x"}]}]}`,
+			expected: `"21"`,
+		},
+		"names are visible inside map": {
+			rule:     `{"let": [{"rate": 0.5}, {"map": [{"var": "items"}, {"*": [{"var": "price"}, {"var": "rate"}]}]}]}`,
+			expected: `[5, 25]`,
+		},
+		"names are visible inside filter": {
+			rule:     `{"let": [{"min": {"/": [{"var": "price"}, 4]}}, {"filter": [{"var": "items"}, {">": [{"val": "price"}, {"var": "min"}]}]}]}`,
+			expected: `[{"price": 50}]`,
+		},
+		"names are visible inside reduce": {
+			rule:     `{"let": [{"fee": 1}, {"reduce": [{"var": "items"}, {"+": [{"var": "accumulator"}, {"var": "current.price"}, {"var": "fee"}]}, 0]}]}`,
+			expected: `62`,
+		},
+		"let inside map sees the element": {
+			rule:     `{"map": [{"var": "items"}, {"let": [{"half": {"/": [{"var": "price"}, 2]}}, {"var": "half"}]}]}`,
+			expected: `[5, 25]`,
+		},
+		"names are gone after the body": {
+			rule:     `{"cat": [{"let": [{"total": 5}, {"var": "total"}]}, {"var": "total"}]}`,
+			expected: `"51"`,
+		},
+		"val reads the names": {
+			rule:     `{"let": [{"last": {"var": "items.1"}}, {"val": ["last", "price"]}]}`,
+			expected: `50`,
+		},
+		"val with a scope reads the data": {
+			rule:     `{"let": [{"total": 5}, {"map": [[1], {"val": [[1], "total"]}]}]}`,
+			expected: `[1]`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(fmt.Sprintf("SCENARIO:%s", name), func(t *testing.T) {
+			var result bytes.Buffer
+			err := jsonlogic.Apply(strings.NewReader(scenario.rule), strings.NewReader(data), &result)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, result.String())
+		})
+	}
+}
+
+func TestLetWithInvalidArguments(t *testing.T) {
+	rules := []string{
+		`{"let": [{"x": 1}]}`,
+		`{"let": [[1], {"var": "x"}]}`,
+		`{"let": [{"": 1}, 1]}`,
+		`{"let": [{"a.b": 1}, 1]}`,
+		`{"let": [{"/a": 1}, 1]}`,
+	}
+
+	for _, rule := range rules {
+		_, err := jsonlogic.ApplyRaw(json.RawMessage(rule), nil)
+		assert.Error(t, err, rule)
+	}
+}
+
+func TestLetWithSharedSubexpressions(t *testing.T) {
+	rules := []json.RawMessage{
+		json.RawMessage(`{"+": [{"var": "a"}, 1]}`),
+		json.RawMessage(`{"let": [{"a": 10}, {"+": [{"var": "a"}, 1]}]}`),
+		json.RawMessage(`{"let": [{"b": {"+": [{"var": "a"}, 1]}}, {"var": "b"}]}`),
+	}
+
+	results, err := jsonlogic.ApplyMany(rules, json.RawMessage(`{"a": 1}`), jsonlogic.ManyOptions{ShareSubexpressions: true})
+	assert.NoError(t, err)
+
+	var values []any
+	for _, result := range results {
+		assert.NoError(t, result.Err)
+		values = append(values, result.Value)
+	}
+	assert.Equal(t, []any{float64(2), float64(11), float64(2)}, values)
+}
+
+func TestLetWithMatcher(t *testing.T) {
+	matcher := jsonlogic.NewMatcher()
+	assert.NoError(t, matcher.AddRaw("expensive", json.RawMessage(`{"let": [{"gross": {"+": [{"var": "price"}, {"var": "tax"}]}}, {">": [{"var": "gross"}, 100]}]}`)))
+
+	matched, err := matcher.MatchRaw(json.RawMessage(`{"price": 90, "tax": 20}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"expensive"}, matched)
+
+	matched, err = matcher.MatchRaw(json.RawMessage(`{"price": 70, "tax": 20}`))
+	assert.NoError(t, err)
+	assert.Empty(t, matched)
+}
+
+func TestGetJsonLogicWithSolvedVarsWithLet(t *testing.T) {
+	rule := json.RawMessage(`{"let": [
+		{"gross": {"+": [{"var": "price"}, {"var": "tax"}]}, "price": 1},
+		{"and": [
+			{">": [{"var": "gross"}, {"var": "limit"}]},
+			{"==": [{"var": "price"}, 1]}
+		]}
+	]}`)
+	data := json.RawMessage(`{"price": 90, "tax": 20, "limit": 100, "gross": 0}`)
+
+	output, err := jsonlogic.GetJsonLogicWithSolvedVars(rule, data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"let": [
+		{"gross": {"+": [90, 20]}, "price": 1},
+		{"and": [
+			{">": [{"var": "gross"}, 100]},
+			{"==": [{"var": "price"}, 1]}
+		]}
+	]}`, string(output))
+}
+
+func TestVariablesWithLet(t *testing.T) {
+	var rule any
+	err := json.Unmarshal([]byte(`{"let": [
+		{"gross": {"+": [{"var": "price"}, {"var": "tax"}]}},
+		{"and": [
+			{">": [{"var": "gross.amount"}, {"var": "limit"}]},
+			{"let": [{"limit": 1}, {"==": [{"var": "limit"}, {"val": "currency"}]}]},
+			{"missing": ["gross"]}
+		]}
+	]}`), &rule)
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, path := range jsonlogic.Variables(rule) {
+		paths = append(paths, path.String())
+	}
+
+	assert.Equal(t, []string{"price", "tax", "limit", "currency", "gross"}, paths)
+}
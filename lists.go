@@ -17,25 +17,25 @@ func (e ErrReduceDataType) Error() string {
 	return fmt.Sprintf("The type \"%s\" is not supported", e.dataType)
 }
 
-func extractSubject(parsed []any, data any) any {
+func (e *evaluator) extractSubject(parsed []any, data any) any {
 	var subject any
 
 	if s, ok := parsed[0].([]any); ok {
 		subject = s
 	} else if m, ok := parsed[0].(map[string]any); ok {
-		subject = apply(m, data)
+		subject = e.apply(m, data)
 	}
 
 	return subject
 }
 
-func filter(values, data any) any {
+func (e *evaluator) filter(values, data any) any {
 	parsed := values.([]any)
 	if len(parsed) < 2 {
 		return []any{}
 	}
 
-	subject := extractSubject(parsed, data)
+	subject := e.extractSubject(parsed, data)
 	if subject == nil {
 		return []any{}
 	}
@@ -47,10 +47,13 @@ func filter(values, data any) any {
 	// Assuming at least half might pass the filter (heuristic)
 	result := make([]any, 0, subjectLen/2)
 
-	logic := solveVars(parsed[1], data)
+	logic := parsed[1]
+
+	defer e.enterScope(subjectSlice, true)()
 
-	for _, value := range subjectSlice {
-		v := parseValues(logic, value)
+	for i, value := range subjectSlice {
+		e.setElement(i, value)
+		v := e.parseValues(logic, value)
 
 		if javascript.IsTrue(v) {
 			result = append(result, value)
@@ -60,13 +63,13 @@ func filter(values, data any) any {
 	return result
 }
 
-func _map(values, data any) any {
+func (e *evaluator) _map(values, data any) any {
 	parsed := values.([]any)
 	if len(parsed) < 2 {
 		return []any{}
 	}
 
-	subject := extractSubject(parsed, data)
+	subject := e.extractSubject(parsed, data)
 	if subject == nil {
 		return []any{}
 	}
@@ -78,15 +81,18 @@ func _map(values, data any) any {
 
 	logic := parsed[1]
 
-	for _, value := range subjectSlice {
-		v := parseValues(logic, value)
+	defer e.enterScope(subjectSlice, false)()
+
+	for i, value := range subjectSlice {
+		e.setElement(i, value)
+		v := e.parseValues(logic, value)
 		result = append(result, v)
 	}
 
 	return result
 }
 
-func reduce(values, data any) any {
+func (e *evaluator) reduce(values, data any) any {
 	parsed := values.([]any)
 	if len(parsed) < 3 {
 		return float64(0)
@@ -100,7 +106,7 @@ func reduce(values, data any) any {
 	{
 		initialValue := parsed[2]
 		if m, ok := initialValue.(map[string]any); ok {
-			initialValue = apply(m, data)
+			initialValue = e.apply(m, data)
 		}
 
 		switch v := initialValue.(type) {
@@ -126,19 +132,24 @@ func reduce(values, data any) any {
 		"valueType":   valueType,
 	}
 
-	subject := extractSubject(parsed, data)
+	subject := e.extractSubject(parsed, data)
 	if subject == nil {
 		return float64(0)
 	}
 
-	for _, value := range subject.([]any) {
-		if value == nil {
+	subjectSlice := subject.([]any)
+
+	defer e.enterScope(subjectSlice, false)()
+
+	for i, value := range subjectSlice {
+		if value == nil && e.profile == ProfileLegacy {
 			continue
 		}
 
 		context["current"] = value
+		e.setElement(i, context)
 
-		v := apply(parsed[1], context)
+		v := e.apply(parsed[1], context)
 
 		switch context["valueType"] {
 		case "bool":
@@ -153,8 +164,8 @@ func reduce(values, data any) any {
 	return context["accumulator"]
 }
 
-func _in(values, data any) any {
-	parsed := parseValues(values, data).([]any)
+func (e *evaluator) _in(values, data any) any {
+	parsed := e.parseValues(values, data).([]any)
 
 	a := parsed[0]
 	var b any
@@ -163,6 +174,9 @@ func _in(values, data any) any {
 	}
 
 	if bs, ok := b.(string); ok {
+		if e.profile != ProfileLegacy {
+			return strings.Contains(bs, jsString(a))
+		}
 		return strings.Contains(bs, a.(string))
 	}
 
@@ -196,8 +210,8 @@ func _in(values, data any) any {
 	return false
 }
 
-func merge(values, data any) any {
-	values = parseValues(values, data)
+func (e *evaluator) merge(values, data any) any {
+	values = e.parseValues(values, data)
 	if isPrimitive(values) {
 		return []any{values}
 	}
@@ -232,8 +246,8 @@ func merge(values, data any) any {
 	return result
 }
 
-func missing(values, data any) any {
-	values = parseValues(values, data)
+func (e *evaluator) missing(values, data any) any {
+	values = e.parseValues(values, data)
 	if _, ok := values.(string); ok {
 		values = []any{values}
 	}
@@ -246,7 +260,7 @@ func missing(values, data any) any {
 	missing := make([]any, 0, len(s))
 
 	for _, _var := range s {
-		_value := getVar(_var, data)
+		_value := readPath(_var, data)
 
 		if _value == nil {
 			missing = append(missing, _var)
@@ -256,8 +270,8 @@ func missing(values, data any) any {
 	return missing
 }
 
-func missingSome(values, data any) any {
-	parsed := parseValues(values, data).([]any)
+func (e *evaluator) missingSome(values, data any) any {
+	parsed := e.parseValues(values, data).([]any)
 	number := int(toNumber(parsed[0]))
 
 	vars, ok := parsed[1].([]any)
@@ -269,7 +283,7 @@ func missingSome(values, data any) any {
 	foundCount := 0
 
 	for _, _var := range vars {
-		if getVar(_var, data) == nil {
+		if readPath(_var, data) == nil {
 			missing = append(missing, _var)
 		} else {
 			foundCount++
@@ -283,18 +297,22 @@ func missingSome(values, data any) any {
 	return []any{}
 }
 
-func all(values, data any) any {
+func (e *evaluator) all(values, data any) any {
 	parsed := values.([]any)
 
-	subject := extractSubject(parsed, data)
+	subject := e.extractSubject(parsed, data)
 	if !javascript.IsTrue(subject) {
 		return false
 	}
 
-	conditions := solveVars(parsed[1], data)
+	conditions := parsed[1]
+	subjectSlice := subject.([]any)
+
+	defer e.enterScope(subjectSlice, true)()
 
-	for _, value := range subject.([]any) {
-		v := apply(conditions, value)
+	for i, value := range subjectSlice {
+		e.setElement(i, value)
+		v := e.apply(conditions, value)
 
 		if !javascript.IsTrue(v) {
 			return false
@@ -304,19 +322,23 @@ func all(values, data any) any {
 	return true
 }
 
-func none(values, data any) any {
+func (e *evaluator) none(values, data any) any {
 	parsed := values.([]any)
 
-	subject := extractSubject(parsed, data)
+	subject := e.extractSubject(parsed, data)
 
 	if !javascript.IsTrue(subject) {
 		return true
 	}
 
-	conditions := solveVars(parsed[1], data)
+	conditions := parsed[1]
+	subjectSlice := subject.([]any)
+
+	defer e.enterScope(subjectSlice, true)()
 
-	for _, value := range subject.([]any) {
-		v := apply(conditions, value)
+	for i, value := range subjectSlice {
+		e.setElement(i, value)
+		v := e.apply(conditions, value)
 
 		if javascript.IsTrue(v) {
 			return false
@@ -326,17 +348,22 @@ func none(values, data any) any {
 	return true
 }
 
-func some(values, data any) any {
+func (e *evaluator) some(values, data any) any {
 	parsed := values.([]any)
-	subject := extractSubject(parsed, data)
+	subject := e.extractSubject(parsed, data)
 
 	if !javascript.IsTrue(subject) {
 		return false
 	}
 
-	conditions := solveVars(parsed[1], data)
-	for _, value := range subject.([]any) {
-		v := apply(conditions, value)
+	conditions := parsed[1]
+	subjectSlice := subject.([]any)
+
+	defer e.enterScope(subjectSlice, true)()
+
+	for i, value := range subjectSlice {
+		e.setElement(i, value)
+		v := e.apply(conditions, value)
 
 		if javascript.IsTrue(v) {
 			return true
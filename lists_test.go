@@ -139,3 +139,86 @@ func TestAllSeesOuterDataVarWithRelativeElementVar(t *testing.T) {
 	assert.NoError(t, err)
 	assert.JSONEq(t, `true`, result.String())
 }
+
+func TestValReadsScopes(t *testing.T) {
+	data := `{
+		"limit": 2,
+		"discount": {"rate": 0.5},
+		"orders": [
+			{"id": "a", "items": [{"price": 10}, {"price": 30}]},
+			{"id": "b", "items": [{"price": 1}]}
+		]
+	}`
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"current element": {
+			rule:     `{"map": [[1, 2, 3], {"*": [{"val": []}, 2]}]}`,
+			expected: `[2, 4, 6]`,
+		},
+		"key with a dot": {
+			rule:     `{"val": "a.b"}`,
+			expected: `null`,
+		},
+		"index of the element": {
+			rule:     `{"map": [["a", "b"], {"cat": [{"val": [[-1], "index"]}, ":", {"val": []}]}]}`,
+			expected: `["0:a", "1:b"]`,
+		},
+		"whole array": {
+			rule:     `{"map": [[3, 4], {"val": [[-1], "array", 0]}]}`,
+			expected: `[3, 3]`,
+		},
+		"parent scope": {
+			rule:     `{"filter": [{"var": "orders"}, {">": [{"reduce": [{"val": "items"}, {"+": [{"var": "accumulator"}, {"var": "current.price"}]}, 0]}, {"val": [[1], "limit"]}]}]}`,
+			expected: `[{"id": "a", "items": [{"price": 10}, {"price": 30}]}]`,
+		},
+		"two levels up": {
+			rule:     `{"map": [{"var": "orders"}, {"map": [{"val": "items"}, {"*": [{"val": "price"}, {"val": [[2], "discount", "rate"]}]}]}]}`,
+			expected: `[[5, 15], [0.5]]`,
+		},
+		"index of the enclosing iteration": {
+			rule:     `{"map": [{"var": "orders"}, {"map": [{"val": "items"}, {"cat": [{"val": [[-2], "index"]}, "-", {"val": [[-1], "index"]}]}]}]}`,
+			expected: `[["0-0", "0-1"], ["1-0"]]`,
+		},
+		"beyond the root": {
+			rule:     `{"val": [[1], "limit"]}`,
+			expected: `null`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			var result bytes.Buffer
+			err := jsonlogic.Apply(strings.NewReader(scenario.rule), strings.NewReader(data), &result)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, result.String())
+		})
+	}
+}
+
+// TestAllSeesOuterDataVarHoldingAnObject makes sure that a variable from the
+// outer data holding an object is used as a value inside the iteration rather
+// than evaluated as a rule.
+func TestAllSeesOuterDataVarHoldingAnObject(t *testing.T) {
+	rule := strings.NewReader(`{"all": [{"var": "items"}, {"!!": [{"var": "options"}]}]}`)
+	data := strings.NewReader(`{"items": [1, 2], "options": {"debug": true}}`)
+
+	var result bytes.Buffer
+	err := jsonlogic.Apply(rule, data, &result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `true`, result.String())
+}
+
+// TestSomeInsideFilterSeesEveryEnclosingScope resolves variables from the
+// document and from the element of the outer iteration inside a nested one.
+func TestSomeInsideFilterSeesEveryEnclosingScope(t *testing.T) {
+	rule := strings.NewReader(`{"filter": [{"var": "users"}, {"some": [{"var": ".roles"}, {"in": [{"var": ""}, {"var": "allowed"}]}]}]}`)
+	data := strings.NewReader(`{"allowed": ["admin"], "users": [{"name": "ana", "roles": ["admin"]}, {"name": "bia", "roles": ["guest"]}]}`)
+
+	var result bytes.Buffer
+	err := jsonlogic.Apply(rule, data, &result)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[{"name": "ana", "roles": ["admin"]}]`, result.String())
+}
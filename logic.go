@@ -4,14 +4,14 @@ import (
 	"github.com/diegoholiveira/jsonlogic/v3/internal/javascript"
 )
 
-func _and(values, data any) any {
+func (e *evaluator) _and(values, data any) any {
 	s := values.([]any)
 	if len(s) == 0 {
 		return nil
 	}
 	var last any
 	for _, value := range s {
-		last = parseValues(value, data)
+		last = e.parseValues(value, data)
 		if !javascript.IsTrue(last) {
 			return last
 		}
@@ -19,14 +19,14 @@ func _and(values, data any) any {
 	return last
 }
 
-func _or(values, data any) any {
+func (e *evaluator) _or(values, data any) any {
 	s := values.([]any)
 	if len(s) == 0 {
 		return nil
 	}
 	var last any
 	for _, value := range s {
-		last = parseValues(value, data)
+		last = e.parseValues(value, data)
 		if javascript.IsTrue(last) {
 			return last
 		}
@@ -34,17 +34,19 @@ func _or(values, data any) any {
 	return last
 }
 
-func evaluateClause(clause any, data any) any {
-	parsed := parseValues(clause, data)
+func (e *evaluator) evaluateClause(clause any, data any) any {
+	parsed := e.parseValues(clause, data)
 
 	if m, ok := parsed.(map[string]any); ok {
-		return apply(m, data)
+		return e.apply(m, data)
 	}
 
 	return parsed
 }
 
-func conditional(values, data any) any {
+func (e *evaluator) conditional(values, data any) any {
+	node := e.node
+
 	values = values.([]any)
 
 	clauses := values.([]any)
@@ -57,26 +59,57 @@ func conditional(values, data any) any {
 
 	// Evaluate each if/then pair
 	for i := 0; i < length-1; i = i + 2 {
-		condition := parseValues(clauses[i], data)
+		condition := e.parseValues(clauses[i], data)
 
 		// If the condition is true, evaluate and return the then clause
 		if javascript.IsTrue(condition) {
-			return evaluateClause(clauses[i+1], data)
+			if e.coverage != nil {
+				e.coverage.branch(node, i/2)
+			}
+			return e.evaluateClause(clauses[i+1], data)
 		}
 	}
 
+	if e.coverage != nil {
+		e.coverage.branch(node, length/2)
+	}
+
 	// If no matches and there is an odd number of clauses, evaluate and return the else clause
 	if length%2 == 1 {
-		return evaluateClause(clauses[length-1], data)
+		return e.evaluateClause(clauses[length-1], data)
 	}
 
 	return nil
 }
 
-func negative(values, data any) any {
-	values = parseValues(values, data)
+func (e *evaluator) negative(values, data any) any {
+	values = e.parseValues(values, data)
 	if s, ok := values.([]any); ok && len(s) > 0 {
 		return !javascript.IsTrue(s[0])
 	}
 	return !javascript.IsTrue(values)
 }
+
+// try returns the result of the first argument evaluated without errors. When
+// every argument fails, the error of the last one is raised.
+func (e *evaluator) try(values, data any) any {
+	s, ok := values.([]any)
+	if !ok {
+		s = []any{values}
+	}
+
+	var err error
+	for _, value := range s {
+		var result any
+		result, err = e.guarded(value, data)
+		if err == nil {
+			return result
+		}
+	}
+
+	if err != nil {
+		panic(err)
+	}
+
+	return nil
+}
@@ -0,0 +1,177 @@
+package jsonlogic
+
+import (
+	"encoding/json"
+	"reflect"
+)
+
+// ManyOptions configures EvaluateAll and ApplyMany.
+type ManyOptions struct {
+	// ShareSubexpressions evaluates the operations that appear more than once,
+	// in the same rule or across rules, only once. Operations inside the body
+	// of map, filter, reduce, all, some and none are not shared, since their
+	// data changes on every element, and neither are the ones inside the body
+	// of let. Custom operators are assumed to always return the same result
+	// for the same input.
+	ShareSubexpressions bool
+}
+
+// RuleResult is the outcome of a single rule evaluated by EvaluateAll or ApplyMany.
+type RuleResult struct {
+	Value any
+	Err   error
+}
+
+// EvaluateAll applies every compiled rule to the same data, which must contain
+// only JSON-compatible types, with the options it was compiled with. The
+// failure of a rule is reported in its result and doesn't prevent the other
+// rules from being evaluated. Operations are only shared between rules
+// compiled with the same options.
+//
+// Parameters:
+//   - rules: the compiled rules, by name
+//   - data: the data every rule is applied to
+//   - opts: settings of the evaluation
+//
+// Returns:
+//   - map[string]RuleResult: the result of each rule, by name
+//   - error: error if the data contains unsupported types
+func EvaluateAll(rules map[string]*Rule, data any, opts ManyOptions) (map[string]RuleResult, error) {
+	if err := scanForUnsupportedTypes(data); err != nil {
+		return nil, err
+	}
+
+	// the rules compiled with the same options share an evaluator
+	groups := make(map[Options][]string)
+	for name, rule := range rules {
+		groups[rule.opts] = append(groups[rule.opts], name)
+	}
+
+	results := make(map[string]RuleResult, len(rules))
+	for ruleOpts, names := range groups {
+		logics := make([]any, len(names))
+		for i, name := range names {
+			logics[i] = rules[name].logic
+		}
+
+		e := newManyEvaluator(logics, opts, ruleOpts)
+
+		for i, name := range names {
+			value, err := e.evaluate(logics[i], data)
+			results[name] = RuleResult{Value: value, Err: err}
+		}
+	}
+
+	return results, nil
+}
+
+// ApplyMany decodes the data once and applies every rule to it. A rule that
+// can't be decoded or fails reports the error in its result, at the same
+// position as the rule.
+//
+// Parameters:
+//   - rules: the JSON Logic rules
+//   - data: json.RawMessage containing the data every rule is applied to
+//   - opts: settings of the evaluation
+//
+// Returns:
+//   - []RuleResult: the result of each rule
+//   - error: error if the data can't be decoded
+func ApplyMany(rules []json.RawMessage, data json.RawMessage, opts ManyOptions) ([]RuleResult, error) {
+	if data == nil {
+		data = json.RawMessage("{}")
+	}
+
+	var _data any
+
+	err := json.Unmarshal(data, &_data)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]RuleResult, len(rules))
+	logics := make([]any, len(rules))
+
+	for i, rule := range rules {
+		results[i].Err = json.Unmarshal(rule, &logics[i])
+	}
+
+	e := newManyEvaluator(logics, opts, Options{})
+
+	for i, logic := range logics {
+		if results[i].Err != nil {
+			continue
+		}
+		results[i].Value, results[i].Err = e.evaluate(logic, _data)
+	}
+
+	return results, nil
+}
+
+func newManyEvaluator(rules []any, opts ManyOptions, ruleOpts Options) *evaluator {
+	e := newEvaluatorWithOptions(ruleOpts)
+	if !opts.ShareSubexpressions {
+		return e
+	}
+
+	nodes := make(map[string][]uintptr)
+	for _, rule := range rules {
+		collectOperations(rule, nodes)
+	}
+
+	e.shared = make(map[uintptr]string)
+	e.memo = make(map[string]any)
+
+	for key, pointers := range nodes {
+		if len(pointers) < 2 {
+			continue
+		}
+		for _, pointer := range pointers {
+			e.shared[pointer] = key
+		}
+	}
+
+	return e
+}
+
+// collectOperations groups the operations found in the rule by their canonical form.
+func collectOperations(rule any, nodes map[string][]uintptr) {
+	switch value := rule.(type) {
+	case map[string]any:
+		if len(value) != 1 {
+			return
+		}
+
+		key, err := canonicalKey(value)
+		if err != nil {
+			return
+		}
+
+		pointer := reflect.ValueOf(value).Pointer()
+		for _, seen := range nodes[key] {
+			if seen == pointer {
+				return
+			}
+		}
+		nodes[key] = append(nodes[key], pointer)
+
+		for _, values := range value {
+			collectOperations(values, nodes)
+		}
+	case []any:
+		for _, item := range value {
+			collectOperations(item, nodes)
+		}
+	}
+}
+
+// canonicalKey returns the JSON encoding of the rule, in which object keys
+// are sorted, so equal rules have the same key.
+func canonicalKey(rule any) (string, error) {
+	encoded, err := json.Marshal(rule)
+	if err != nil {
+		return "", err
+	}
+
+	return string(encoded), nil
+}
@@ -0,0 +1,127 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestApplyMany(t *testing.T) {
+	rules := []json.RawMessage{
+		json.RawMessage(`{"==": [{"var": "country"}, "BR"]}`),
+		json.RawMessage(`{"+": [{"var": "a"}, {"var": "b"}]}`),
+		json.RawMessage(`{"invalid`),
+		json.RawMessage(`{"unknown_operator": []}`),
+		json.RawMessage(`{"map": [{"var": "items"}, {"*": [{"var": ""}, 2]}]}`),
+	}
+
+	for _, share := range []bool{false, true} {
+		results, err := jsonlogic.ApplyMany(rules, json.RawMessage(`{"country": "BR", "a": 1, "b": 2, "items": [1, 2]}`), jsonlogic.ManyOptions{
+			ShareSubexpressions: share,
+		})
+		assert.NoError(t, err)
+		assert.Len(t, results, len(rules))
+
+		assert.Equal(t, jsonlogic.RuleResult{Value: true}, results[0])
+		assert.Equal(t, jsonlogic.RuleResult{Value: float64(3)}, results[1])
+		assert.Error(t, results[2].Err)
+		assert.Error(t, results[3].Err)
+		assert.Equal(t, jsonlogic.RuleResult{Value: []any{float64(2), float64(4)}}, results[4])
+	}
+
+	_, err := jsonlogic.ApplyMany(rules, json.RawMessage(`{`), jsonlogic.ManyOptions{})
+	assert.Error(t, err)
+}
+
+func TestEvaluateAll(t *testing.T) {
+	compile := func(rule string) *jsonlogic.Rule {
+		compiled, err := jsonlogic.Compile(json.RawMessage(rule))
+		assert.NoError(t, err)
+		return compiled
+	}
+
+	rules := map[string]*jsonlogic.Rule{
+		"adult":   compile(`{">=": [{"var": "age"}, 18]}`),
+		"missing": compile(`{"missing": ["name", "age"]}`),
+	}
+
+	results, err := jsonlogic.EvaluateAll(rules, map[string]any{"age": float64(20)}, jsonlogic.ManyOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]jsonlogic.RuleResult{
+		"adult":   {Value: true},
+		"missing": {Value: []any{"name"}},
+	}, results)
+
+	_, err = jsonlogic.EvaluateAll(rules, map[string]any{"age": 20}, jsonlogic.ManyOptions{})
+	assert.Error(t, err)
+}
+
+func TestEvaluateAllWithOptions(t *testing.T) {
+	compile := func(rule string, opts jsonlogic.Options) *jsonlogic.Rule {
+		compiled, err := jsonlogic.CompileWithOptions(json.RawMessage(rule), opts)
+		assert.NoError(t, err)
+		return compiled
+	}
+
+	rules := map[string]*jsonlogic.Rule{
+		"loose":  compile(`{"==": [{"var": "code"}, 10]}`, jsonlogic.Options{}),
+		"strict": compile(`{"==": [{"var": "code"}, 10]}`, jsonlogic.Options{Strict: true}),
+		"legacy": compile(`{"cat": [" ice ", {"var": "flavor"}]}`, jsonlogic.Options{}),
+		"js":     compile(`{"cat": [" ice ", {"var": "flavor"}]}`, jsonlogic.Options{Profile: jsonlogic.ProfileJsonLogicJS}),
+	}
+
+	data := map[string]any{"code": "10", "flavor": "cream "}
+
+	for _, share := range []bool{false, true} {
+		results, err := jsonlogic.EvaluateAll(rules, data, jsonlogic.ManyOptions{ShareSubexpressions: share})
+		assert.NoError(t, err)
+
+		for name, rule := range rules {
+			value, err := rule.Apply(data)
+			assert.Equal(t, jsonlogic.RuleResult{Value: value, Err: err}, results[name], name)
+		}
+
+		assert.Equal(t, true, results["loose"].Value)
+		assert.Error(t, results["strict"].Err)
+		assert.Equal(t, "ice cream", results["legacy"].Value)
+		assert.Equal(t, " ice cream ", results["js"].Value)
+	}
+}
+
+func TestEvaluateAllSharesSubexpressions(t *testing.T) {
+	var calls int32
+	jsonlogic.AddOperator("counted_lookup", func(values, data any) any {
+		atomic.AddInt32(&calls, 1)
+		return values
+	})
+
+	compile := func(rule string) *jsonlogic.Rule {
+		compiled, err := jsonlogic.Compile(json.RawMessage(rule))
+		assert.NoError(t, err)
+		return compiled
+	}
+
+	rules := map[string]*jsonlogic.Rule{
+		"first":  compile(`{"==": [{"counted_lookup": [{"var": "a"}]}, 1]}`),
+		"second": compile(`{"+": [{"counted_lookup": [{"var": "a"}]}, {"counted_lookup": [{"var": "a"}]}]}`),
+		"inside": compile(`{"map": [[1, 2, 3], {"counted_lookup": [{"var": "a"}]}]}`),
+	}
+
+	data := map[string]any{"a": float64(1)}
+
+	results, err := jsonlogic.EvaluateAll(rules, data, jsonlogic.ManyOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, int32(6), atomic.LoadInt32(&calls))
+
+	atomic.StoreInt32(&calls, 0)
+
+	shared, err := jsonlogic.EvaluateAll(rules, data, jsonlogic.ManyOptions{ShareSubexpressions: true})
+	assert.NoError(t, err)
+	assert.Equal(t, results, shared)
+	// evaluated once at the top level and once per element inside map
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,656 @@
+package jsonlogic
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/diegoholiveira/jsonlogic/v3/internal/javascript"
+)
+
+// Matcher finds which rules, out of a large set, match a single data document.
+//
+// Instead of evaluating every rule, the matcher indexes each rule by the
+// equality, "in" and range predicates it places on variables. When matching,
+// it only looks up the values of the indexed variables, collects the rules
+// whose predicates can hold for those values and confirms them with the
+// regular evaluator. Rules without an indexable predicate are always confirmed.
+//
+// The conditions joined by "and", "or" and "!" are evaluated at most once per
+// call to Match, so identical conditions shared by many rules cost a single
+// evaluation.
+//
+// A Matcher is safe for concurrent use.
+type Matcher struct {
+	lock sync.RWMutex
+
+	rules  map[string]*matcherRule
+	equals map[string]map[string]map[string]struct{}
+	lowers map[string][]matcherBound
+	uppers map[string][]matcherBound
+	always map[string]struct{}
+}
+
+type matcherRule struct {
+	id      string
+	tree    *matcherNode
+	anchors []matcherAnchor
+}
+
+// matcherNode is a rule split on its boolean connectives. Leaves hold the
+// remaining conditions along with their canonical form, used to share
+// results between rules.
+type matcherNode struct {
+	op       string
+	children []*matcherNode
+	key      string
+	logic    any
+}
+
+type matcherAnchorKind int
+
+const (
+	anchorEquals matcherAnchorKind = iota
+	anchorLower
+	anchorUpper
+)
+
+// matcherAnchor is a predicate on a variable that must hold for the rule to be true.
+type matcherAnchor struct {
+	path      string
+	kind      matcherAnchorKind
+	keys      []string
+	bound     float64
+	inclusive bool
+}
+
+type matcherBound struct {
+	value     float64
+	inclusive bool
+	id        string
+}
+
+// NewMatcher creates an empty Matcher.
+func NewMatcher() *Matcher {
+	return &Matcher{
+		rules:  make(map[string]*matcherRule),
+		equals: make(map[string]map[string]map[string]struct{}),
+		lowers: make(map[string][]matcherBound),
+		uppers: make(map[string][]matcherBound),
+		always: make(map[string]struct{}),
+	}
+}
+
+// Add registers a rule under the given id, replacing any rule previously
+// registered with the same id. The rule must contain only JSON-compatible
+// types, as required by ApplyInterface, and be valid JSON Logic.
+func (m *Matcher) Add(id string, rule any) error {
+	if err := scanForUnsupportedTypes(rule); err != nil {
+		return err
+	}
+
+	if !ValidateJsonLogic(rule) {
+		return fmt.Errorf("jsonlogic: rule %q is not valid", id)
+	}
+
+	tree, err := newMatcherNode(rule)
+	if err != nil {
+		return err
+	}
+
+	r := &matcherRule{
+		id:      id,
+		tree:    tree,
+		anchors: matcherAnchors(rule),
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	m.remove(id)
+	m.rules[id] = r
+
+	if r.anchors == nil {
+		m.always[id] = struct{}{}
+		return nil
+	}
+
+	for _, anchor := range r.anchors {
+		switch anchor.kind {
+		case anchorEquals:
+			keys, ok := m.equals[anchor.path]
+			if !ok {
+				keys = make(map[string]map[string]struct{})
+				m.equals[anchor.path] = keys
+			}
+			for _, key := range anchor.keys {
+				if keys[key] == nil {
+					keys[key] = make(map[string]struct{})
+				}
+				keys[key][id] = struct{}{}
+			}
+		case anchorLower:
+			m.lowers[anchor.path] = insertBound(m.lowers[anchor.path], matcherBound{anchor.bound, anchor.inclusive, id})
+		case anchorUpper:
+			m.uppers[anchor.path] = insertBound(m.uppers[anchor.path], matcherBound{anchor.bound, anchor.inclusive, id})
+		}
+	}
+
+	return nil
+}
+
+// AddRaw decodes the rule and registers it under the given id. See Add.
+func (m *Matcher) AddRaw(id string, rule json.RawMessage) error {
+	var _rule any
+
+	err := json.Unmarshal(rule, &_rule)
+	if err != nil {
+		return err
+	}
+
+	return m.Add(id, _rule)
+}
+
+// Remove unregisters the rule with the given id. It reports whether the rule existed.
+func (m *Matcher) Remove(id string) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.remove(id)
+}
+
+// Len returns the number of registered rules.
+func (m *Matcher) Len() int {
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	return len(m.rules)
+}
+
+func (m *Matcher) remove(id string) bool {
+	r, ok := m.rules[id]
+	if !ok {
+		return false
+	}
+
+	delete(m.rules, id)
+	delete(m.always, id)
+
+	for _, anchor := range r.anchors {
+		switch anchor.kind {
+		case anchorEquals:
+			keys := m.equals[anchor.path]
+			for _, key := range anchor.keys {
+				delete(keys[key], id)
+				if len(keys[key]) == 0 {
+					delete(keys, key)
+				}
+			}
+			if len(keys) == 0 {
+				delete(m.equals, anchor.path)
+			}
+		case anchorLower:
+			m.lowers[anchor.path] = removeBound(m.lowers[anchor.path], id)
+		case anchorUpper:
+			m.uppers[anchor.path] = removeBound(m.uppers[anchor.path], id)
+		}
+	}
+
+	return true
+}
+
+// Match returns the ids, in ascending order, of the rules whose result is
+// truthy for the given data. The data must contain only JSON-compatible types.
+// It stops and returns an error when a rule fails to evaluate.
+func (m *Matcher) Match(data any) ([]string, error) {
+	if err := scanForUnsupportedTypes(data); err != nil {
+		return nil, err
+	}
+
+	m.lock.RLock()
+	defer m.lock.RUnlock()
+
+	candidates := m.candidates(data)
+
+	ids := make([]string, 0, len(candidates))
+	for id := range candidates {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	results := make(map[string]bool)
+	matched := make([]string, 0, len(ids))
+
+	for _, id := range ids {
+		ok, err := m.rules[id].tree.eval(data, results)
+		if err != nil {
+			return nil, fmt.Errorf("jsonlogic: rule %q: %w", id, err)
+		}
+
+		if ok {
+			matched = append(matched, id)
+		}
+	}
+
+	return matched, nil
+}
+
+// MatchRaw decodes the data and matches it. See Match.
+func (m *Matcher) MatchRaw(data json.RawMessage) ([]string, error) {
+	if data == nil {
+		data = json.RawMessage("{}")
+	}
+
+	var _data any
+
+	err := json.Unmarshal(data, &_data)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Match(_data)
+}
+
+func (m *Matcher) candidates(data any) map[string]struct{} {
+	candidates := make(map[string]struct{}, len(m.always))
+	for id := range m.always {
+		candidates[id] = struct{}{}
+	}
+
+	e := newEvaluator()
+	values := make(map[string]any)
+	lookup := func(path string) any {
+		v, ok := values[path]
+		if !ok {
+			v = e.getVar(path, data)
+			values[path] = v
+		}
+		return v
+	}
+
+	for path, keys := range m.equals {
+		for _, key := range matcherProbeKeys(lookup(path)) {
+			for id := range keys[key] {
+				candidates[id] = struct{}{}
+			}
+		}
+	}
+
+	for path, bounds := range m.lowers {
+		v := javascript.ToNumber(lookup(path))
+		if math.IsNaN(v) {
+			continue
+		}
+
+		// every bound below the value satisfies "value > bound"
+		end := sort.Search(len(bounds), func(i int) bool { return bounds[i].value > v })
+		for _, bound := range bounds[:end] {
+			if bound.value < v || bound.inclusive {
+				candidates[bound.id] = struct{}{}
+			}
+		}
+	}
+
+	for path, bounds := range m.uppers {
+		v := javascript.ToNumber(lookup(path))
+		if math.IsNaN(v) {
+			continue
+		}
+
+		// every bound above the value satisfies "value < bound"
+		start := sort.Search(len(bounds), func(i int) bool { return bounds[i].value >= v })
+		for _, bound := range bounds[start:] {
+			if bound.value > v || bound.inclusive {
+				candidates[bound.id] = struct{}{}
+			}
+		}
+	}
+
+	return candidates
+}
+
+func insertBound(bounds []matcherBound, bound matcherBound) []matcherBound {
+	i := sort.Search(len(bounds), func(i int) bool { return bounds[i].value > bound.value })
+	bounds = append(bounds, matcherBound{})
+	copy(bounds[i+1:], bounds[i:])
+	bounds[i] = bound
+	return bounds
+}
+
+func removeBound(bounds []matcherBound, id string) []matcherBound {
+	result := bounds[:0]
+	for _, bound := range bounds {
+		if bound.id != id {
+			result = append(result, bound)
+		}
+	}
+	return result
+}
+
+func newMatcherNode(logic any) (*matcherNode, error) {
+	if m, ok := logic.(map[string]any); ok && len(m) == 1 {
+		for operator, values := range m {
+			switch operator {
+			case "and", "or":
+				args, ok := values.([]any)
+				if !ok {
+					break
+				}
+
+				node := &matcherNode{op: operator, children: make([]*matcherNode, 0, len(args))}
+				for _, arg := range args {
+					child, err := newMatcherNode(arg)
+					if err != nil {
+						return nil, err
+					}
+					node.children = append(node.children, child)
+				}
+				return node, nil
+			case "!", "!!":
+				arg := values
+				if args, ok := values.([]any); ok {
+					if len(args) == 0 {
+						break
+					}
+					arg = args[0]
+				}
+
+				child, err := newMatcherNode(arg)
+				if err != nil {
+					return nil, err
+				}
+				return &matcherNode{op: operator, children: []*matcherNode{child}}, nil
+			}
+		}
+	}
+
+	key, err := json.Marshal(logic)
+	if err != nil {
+		return nil, err
+	}
+
+	return &matcherNode{key: string(key), logic: logic}, nil
+}
+
+// eval reports whether the node is truthy for the data, reusing and filling
+// the results of the conditions already evaluated for the same data.
+func (n *matcherNode) eval(data any, results map[string]bool) (bool, error) {
+	switch n.op {
+	case "and":
+		if len(n.children) == 0 {
+			return false, nil
+		}
+		for _, child := range n.children {
+			ok, err := child.eval(data, results)
+			if err != nil || !ok {
+				return false, err
+			}
+		}
+		return true, nil
+	case "or":
+		for _, child := range n.children {
+			ok, err := child.eval(data, results)
+			if err != nil || ok {
+				return ok, err
+			}
+		}
+		return false, nil
+	case "!":
+		ok, err := n.children[0].eval(data, results)
+		return !ok, err
+	case "!!":
+		return n.children[0].eval(data, results)
+	}
+
+	if ok, found := results[n.key]; found {
+		return ok, nil
+	}
+
+	output, err := applyInterfaceUnguarded(n.logic, data)
+	if err != nil {
+		return false, err
+	}
+
+	ok := javascript.IsTrue(output)
+	results[n.key] = ok
+	return ok, nil
+}
+
+// matcherAnchors returns predicates such that, whenever the rule is truthy,
+// at least one of them holds. It returns nil when no such predicates can be
+// derived from the rule.
+func matcherAnchors(logic any) []matcherAnchor {
+	m, ok := logic.(map[string]any)
+	if !ok || len(m) != 1 {
+		return nil
+	}
+
+	for operator, values := range m {
+		args, ok := values.([]any)
+
+		switch operator {
+		case "and":
+			var best []matcherAnchor
+			for _, arg := range args {
+				anchors := matcherAnchors(arg)
+				if anchors != nil && (best == nil || matcherAnchorsScore(anchors) < matcherAnchorsScore(best)) {
+					best = anchors
+				}
+			}
+			return best
+		case "or":
+			if len(args) == 0 {
+				return nil
+			}
+			var all []matcherAnchor
+			for _, arg := range args {
+				anchors := matcherAnchors(arg)
+				if anchors == nil {
+					return nil
+				}
+				all = append(all, anchors...)
+			}
+			return all
+		case "!!":
+			if ok && len(args) > 0 {
+				return matcherAnchors(args[0])
+			}
+			return matcherAnchors(values)
+		case "==", "===":
+			if !ok || len(args) != 2 {
+				return nil
+			}
+			path, literal, found := matcherVarAndLiteral(args[0], args[1])
+			if !found {
+				return nil
+			}
+			keys := matcherEqualKeys(literal)
+			if keys == nil {
+				return nil
+			}
+			return []matcherAnchor{{path: path, kind: anchorEquals, keys: keys}}
+		case "in":
+			if !ok || len(args) != 2 {
+				return nil
+			}
+			path, found := matcherVarPath(args[0])
+			list, isList := args[1].([]any)
+			if !found || !isList {
+				return nil
+			}
+			var keys []string
+			for _, element := range list {
+				elementKeys := matcherInKeys(element)
+				if elementKeys == nil {
+					return nil
+				}
+				keys = append(keys, elementKeys...)
+			}
+			return []matcherAnchor{{path: path, kind: anchorEquals, keys: keys}}
+		case "<", "<=", ">", ">=":
+			if !ok {
+				return nil
+			}
+			return matcherRangeAnchors(operator, args)
+		}
+	}
+
+	return nil
+}
+
+// matcherAnchorsScore ranks alternative anchors: fewer alternatives are more
+// selective, and equality is more selective than a range.
+func matcherAnchorsScore(anchors []matcherAnchor) int {
+	score := 0
+	for _, anchor := range anchors {
+		if anchor.kind == anchorEquals {
+			score += 2
+		} else {
+			score += 3
+		}
+	}
+	return score
+}
+
+func matcherRangeAnchors(operator string, args []any) []matcherAnchor {
+	inclusive := operator == "<=" || operator == ">="
+
+	if len(args) == 3 && (operator == "<" || operator == "<=") {
+		path, found := matcherVarPath(args[1])
+		bound, isNumber := args[0].(float64)
+		if !found || !isNumber {
+			return nil
+		}
+		return []matcherAnchor{{path: path, kind: anchorLower, bound: bound, inclusive: inclusive}}
+	}
+
+	if len(args) != 2 {
+		return nil
+	}
+
+	kind := anchorUpper
+	if operator == ">" || operator == ">=" {
+		kind = anchorLower
+	}
+
+	path, found := matcherVarPath(args[0])
+	bound, isNumber := args[1].(float64)
+	if !found {
+		// the variable is on the right side, so the comparison is flipped
+		path, found = matcherVarPath(args[1])
+		bound, isNumber = args[0].(float64)
+		if kind == anchorUpper {
+			kind = anchorLower
+		} else {
+			kind = anchorUpper
+		}
+	}
+
+	if !found || !isNumber {
+		return nil
+	}
+
+	return []matcherAnchor{{path: path, kind: kind, bound: bound, inclusive: inclusive}}
+}
+
+func matcherVarAndLiteral(a, b any) (string, any, bool) {
+	if path, ok := matcherVarPath(a); ok && matcherIsLiteral(b) {
+		return path, b, true
+	}
+	if path, ok := matcherVarPath(b); ok && matcherIsLiteral(a) {
+		return path, a, true
+	}
+	return "", nil, false
+}
+
+func matcherIsLiteral(value any) bool {
+	return value == nil || isPrimitive(value)
+}
+
+// matcherVarPath returns the path of a "var" without a default value.
+func matcherVarPath(value any) (string, bool) {
+	m, ok := value.(map[string]any)
+	if !ok || len(m) != 1 {
+		return "", false
+	}
+
+	path, ok := m["var"]
+	if !ok {
+		return "", false
+	}
+
+	if s, ok := path.([]any); ok {
+		if len(s) != 1 {
+			return "", false
+		}
+		path = s[0]
+	}
+
+	// wildcards read arrays, which equality and ranges never match
+	p, ok := ParsePath(path)
+	if !ok || p.HasWildcard() {
+		return "", false
+	}
+
+	return p.String(), true
+}
+
+// matcherEqualKeys returns the index keys of a literal compared with "==".
+// Values that are loosely equal share at least one key.
+func matcherEqualKeys(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return []string{"null"}
+	case bool:
+		return []string{matcherNumberKey(javascript.ToNumber(v))}
+	case float64:
+		if math.IsNaN(v) {
+			return nil
+		}
+		return []string{matcherNumberKey(v)}
+	case string:
+		keys := []string{"s:" + v}
+		if n := javascript.ToNumber(v); !math.IsNaN(n) {
+			keys = append(keys, matcherNumberKey(n))
+		}
+		return keys
+	}
+
+	return nil
+}
+
+// matcherInKeys returns the index keys of an element of the list used by "in".
+func matcherInKeys(value any) []string {
+	switch v := value.(type) {
+	case nil:
+		return []string{"null"}
+	case bool:
+		return []string{"b:" + strconv.FormatBool(v)}
+	case float64:
+		return []string{matcherNumberKey(v)}
+	case string:
+		// "in" compares numbers with toNumber, which turns invalid strings into 0
+		return []string{"s:" + v, matcherNumberKey(toNumber(v))}
+	}
+
+	return nil
+}
+
+// matcherProbeKeys returns the index keys to look up for a value found in the data.
+func matcherProbeKeys(value any) []string {
+	keys := matcherEqualKeys(value)
+	if b, ok := value.(bool); ok {
+		keys = append(keys, "b:"+strconv.FormatBool(b))
+	}
+	return keys
+}
+
+func matcherNumberKey(n float64) string {
+	if n == 0 {
+		n = 0 // normalizes negative zero
+	}
+	return "n:" + strconv.FormatFloat(n, 'g', -1, 64)
+}
@@ -0,0 +1,133 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestMatcher(t *testing.T) {
+	rules := map[string]string{
+		"brazil":        `{"==": [{"var": "country"}, "BR"]}`,
+		"iberia":        `{"in": [{"var": "country"}, ["PT", "ES"]]}`,
+		"adult":         `{">=": [{"var": "age"}, 18]}`,
+		"minor":         `{"<": [{"var": "age"}, 18]}`,
+		"young_adult":   `{"<=": [18, {"var": "age"}, 25]}`,
+		"brazil_adult":  `{"and": [{"==": [{"var": "country"}, "BR"]}, {">=": [{"var": "age"}, 18]}]}`,
+		"not_brazil":    `{"!": {"==": [{"var": "country"}, "BR"]}}`,
+		"either":        `{"or": [{"==": [{"var": "country"}, "PT"]}, {">": [{"var": "age"}, 60]}]}`,
+		"numeric_level": `{"==": [{"var": "level"}, 3]}`,
+		"has_name":      `{"!!": {"var": "name"}}`,
+		"always":        `true`,
+	}
+
+	matcher := jsonlogic.NewMatcher()
+	for id, rule := range rules {
+		assert.NoError(t, matcher.AddRaw(id, json.RawMessage(rule)))
+	}
+	assert.Equal(t, len(rules), matcher.Len())
+
+	scenarios := map[string]struct {
+		data     string
+		expected []string
+	}{
+		"adult from brazil": {
+			data:     `{"country": "BR", "age": 30, "level": "3"}`,
+			expected: []string{"adult", "always", "brazil", "brazil_adult", "numeric_level"},
+		},
+		"young adult from portugal": {
+			data:     `{"country": "PT", "age": 20, "name": "Ana"}`,
+			expected: []string{"adult", "always", "either", "has_name", "iberia", "not_brazil", "young_adult"},
+		},
+		"minor without country": {
+			data:     `{"age": 10}`,
+			expected: []string{"always", "minor", "not_brazil"},
+		},
+		"senior from spain": {
+			data:     `{"country": "ES", "age": 70}`,
+			expected: []string{"adult", "always", "either", "iberia", "not_brazil"},
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(fmt.Sprintf("SCENARIO:%s", name), func(t *testing.T) {
+			matched, err := matcher.MatchRaw(json.RawMessage(scenario.data))
+			assert.NoError(t, err)
+			assert.Equal(t, scenario.expected, matched)
+
+			// the index must never change the outcome of the rules
+			var expected []string
+			for id, rule := range rules {
+				result, err := jsonlogic.ApplyRaw(json.RawMessage(rule), json.RawMessage(scenario.data))
+				assert.NoError(t, err)
+
+				var output any
+				assert.NoError(t, json.Unmarshal(result, &output))
+				if output != nil && output != false && output != float64(0) && output != "" {
+					expected = append(expected, id)
+				}
+			}
+			assert.ElementsMatch(t, expected, matched)
+		})
+	}
+}
+
+func TestMatcherAddAndRemove(t *testing.T) {
+	matcher := jsonlogic.NewMatcher()
+
+	assert.NoError(t, matcher.AddRaw("rule", json.RawMessage(`{"==": [{"var": "a"}, 1]}`)))
+	matched, err := matcher.MatchRaw(json.RawMessage(`{"a": 1}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rule"}, matched)
+
+	// replacing a rule drops its previous index entries
+	assert.NoError(t, matcher.AddRaw("rule", json.RawMessage(`{">": [{"var": "a"}, 5]}`)))
+	assert.Equal(t, 1, matcher.Len())
+
+	matched, err = matcher.MatchRaw(json.RawMessage(`{"a": 1}`))
+	assert.NoError(t, err)
+	assert.Empty(t, matched)
+
+	matched, err = matcher.MatchRaw(json.RawMessage(`{"a": 6}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"rule"}, matched)
+
+	assert.True(t, matcher.Remove("rule"))
+	assert.False(t, matcher.Remove("rule"))
+	assert.Equal(t, 0, matcher.Len())
+
+	matched, err = matcher.MatchRaw(json.RawMessage(`{"a": 6}`))
+	assert.NoError(t, err)
+	assert.Empty(t, matched)
+}
+
+func TestMatcherRejectsInvalidRules(t *testing.T) {
+	matcher := jsonlogic.NewMatcher()
+
+	assert.Error(t, matcher.AddRaw("invalid", json.RawMessage(`{"unknown_operator": [1]}`)))
+	assert.Error(t, matcher.AddRaw("not json", json.RawMessage(`{`)))
+	assert.Error(t, matcher.Add("unsupported", map[string]any{"==": []any{1, 1}}))
+	assert.Equal(t, 0, matcher.Len())
+}
+
+func TestMatcherReturnsEvaluationErrors(t *testing.T) {
+	matcher := jsonlogic.NewMatcher()
+	assert.NoError(t, matcher.AddRaw("broken", json.RawMessage(`{"reduce": [[1], {"var": "current"}, null]}`)))
+
+	_, err := matcher.MatchRaw(json.RawMessage(`{}`))
+	assert.Error(t, err)
+}
+
+func TestMatcherWithPathSyntaxes(t *testing.T) {
+	matcher := jsonlogic.NewMatcher()
+	assert.NoError(t, matcher.AddRaw("pointer", json.RawMessage(`{"==": [{"var": "/domains/example.com"}, "active"]}`)))
+	assert.NoError(t, matcher.AddRaw("segments", json.RawMessage(`{"==": [{"var": [["domains", "example.com"]]}, "active"]}`)))
+
+	matched, err := matcher.MatchRaw(json.RawMessage(`{"domains": {"example.com": "active"}}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"pointer", "segments"}, matched)
+}
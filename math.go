@@ -2,8 +2,8 @@ package jsonlogic
 
 import "math"
 
-func mod(values, data any) any {
-	parsed := parseValues(values, data).([]any)
+func (e *evaluator) mod(values, data any) any {
+	parsed := e.numericOperands(values, data).([]any)
 
 	a := toNumber(parsed[0])
 	b := toNumber(parsed[1])
@@ -11,8 +11,8 @@ func mod(values, data any) any {
 	return math.Mod(a, b)
 }
 
-func abs(values, data any) any {
-	parsed := parseValues(values, data)
+func (e *evaluator) abs(values, data any) any {
+	parsed := e.numericOperands(values, data)
 	parsedAsSlice, ok := parsed.([]any)
 	if !ok {
 		return math.Abs(toNumber(parsed))
@@ -25,8 +25,8 @@ func abs(values, data any) any {
 	return math.Abs(toNumber(parsedAsSlice[0]))
 }
 
-func sum(values, data any) any {
-	parsed := parseValues(values, data)
+func (e *evaluator) sum(values, data any) any {
+	parsed := e.numericOperands(values, data)
 	parsedAsSlice, ok := parsed.([]any)
 	if !ok {
 		return toNumber(parsed)
@@ -41,10 +41,10 @@ func sum(values, data any) any {
 	return sum
 }
 
-func minus(values, data any) any {
-	parsed, ok := parseValues(values, data).([]any)
+func (e *evaluator) minus(values, data any) any {
+	parsed, ok := e.operandList(e.numericOperands(values, data))
 	if !ok || len(parsed) == 0 {
-		return 0
+		return e.noOperands("-")
 	}
 
 	if len(parsed) == 1 {
@@ -60,8 +60,8 @@ func minus(values, data any) any {
 	return sum
 }
 
-func mult(values, data any) any {
-	parsed, ok := parseValues(values, data).([]any)
+func (e *evaluator) mult(values, data any) any {
+	parsed, ok := e.numericOperands(values, data).([]any)
 	if !ok || len(parsed) == 0 {
 		return float64(1)
 	}
@@ -75,10 +75,10 @@ func mult(values, data any) any {
 	return sum
 }
 
-func div(values, data any) any {
-	parsed, ok := parseValues(values, data).([]any)
+func (e *evaluator) div(values, data any) any {
+	parsed, ok := e.operandList(e.numericOperands(values, data))
 	if !ok || len(parsed) == 0 {
-		return 0
+		return e.noOperands("/")
 	}
 
 	sum := toNumber(parsed[0])
@@ -90,8 +90,8 @@ func div(values, data any) any {
 	return sum
 }
 
-func max(values, data any) any {
-	parsed, ok := parseValues(values, data).([]any)
+func (e *evaluator) max(values, data any) any {
+	parsed, ok := e.numericOperands(values, data).([]any)
 	if !ok {
 		return nil
 	}
@@ -112,8 +112,8 @@ func max(values, data any) any {
 	return bigger
 }
 
-func min(values, data any) any {
-	parsed, ok := parseValues(values, data).([]any)
+func (e *evaluator) min(values, data any) any {
+	parsed, ok := e.numericOperands(values, data).([]any)
 	if !ok {
 		return nil
 	}
@@ -133,3 +133,30 @@ func min(values, data any) any {
 
 	return smallest
 }
+
+// operandList returns the operands as a list. Outside the legacy profile, a
+// single operand given without an array is a list of one operand.
+func (e *evaluator) operandList(parsed any) ([]any, bool) {
+	if list, ok := parsed.([]any); ok {
+		return list, true
+	}
+
+	if e.profile == ProfileLegacy {
+		return nil, false
+	}
+
+	return []any{parsed}, true
+}
+
+// noOperands returns the result of "-" and "/" without operands, which
+// json-logic-js computes as NaN.
+func (e *evaluator) noOperands(operator string) any {
+	switch e.profile {
+	case ProfileJsonLogicJS:
+		return nil
+	case ProfileCommunitySpec:
+		panic(&ArityError{Operator: operator, Expected: 1, Got: 0})
+	}
+
+	return 0
+}
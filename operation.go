@@ -1,6 +1,7 @@
 package jsonlogic
 
 import (
+	"errors"
 	"fmt"
 	"sync"
 
@@ -21,8 +22,12 @@ func (e ErrInvalidOperator) Error() string {
 	return fmt.Sprintf("The operator \"%s\" is not supported", e.operator)
 }
 
+// operatorFn is the internal signature of every operator, which receives the
+// unparsed values and the evaluator handling the current evaluation.
+type operatorFn func(e *evaluator, values, data any) any
+
 // operators holds custom operators
-var operators = make(map[string]OperatorFn)
+var operators = make(map[string]operatorFn)
 
 var operatorsLock = &sync.RWMutex{}
 
@@ -38,17 +43,164 @@ func AddOperator(key string, cb OperatorFn) {
 	operatorsLock.Lock()
 	defer operatorsLock.Unlock()
 
-	operators[key] = func(values, data any) any {
-		return cb(parseValues(values, data), data)
+	delete(signatures, key)
+
+	operators[key] = func(e *evaluator, values, data any) any {
+		return cb(e.parseValues(values, data), data)
+	}
+}
+
+// OperatorWithErrorFn defines the signature for custom operator functions
+// that can fail. It takes values and data as input and returns a result or an error.
+type OperatorWithErrorFn func(values, data any) (result any, err error)
+
+// OperatorError represents an error returned by a custom operator. It contains
+// the operator name, the location of the operation in the rule as a JSON
+// Pointer (empty for the rule itself) and the error returned by the operator.
+type OperatorError struct {
+	Operator string
+	Path     string
+	Err      error
+}
+
+func (e *OperatorError) Error() string {
+	if e.Path == "" {
+		return fmt.Sprintf("The operator \"%s\" failed: %s", e.Operator, e.Err)
+	}
+
+	return fmt.Sprintf("The operator \"%s\" at \"%s\" failed: %s", e.Operator, e.Path, e.Err)
+}
+
+func (e *OperatorError) Unwrap() error {
+	return e.Err
+}
+
+// AddOperatorWithError registers a custom operator with the given key and function.
+// The operator function will be called with parsed values and the original data context,
+// like the ones registered by AddOperator. The errors it returns are wrapped in an
+// OperatorError and returned by Apply, ApplyRaw and ApplyInterface, unless caught by "try".
+//
+// Parameters:
+//   - key: the operator name to register (e.g., "lookup")
+//   - cb: the function to execute when the operator is encountered
+//
+// Concurrency: This function is safe for concurrent use as it properly locks the operators map.
+func AddOperatorWithError(key string, cb OperatorWithErrorFn) {
+	operatorsLock.Lock()
+	defer operatorsLock.Unlock()
+
+	delete(signatures, key)
+
+	operators[key] = func(e *evaluator, values, data any) any {
+		node := e.node
+
+		result, err := cb(e.parseValues(values, data), data)
+		if err != nil {
+			panic(e.operatorError(key, node, err))
+		}
+
+		return result
+	}
+}
+
+// operatorError wraps the error returned by the operation in an OperatorError,
+// unless it already holds one.
+func (e *evaluator) operatorError(operator string, node map[string]any, err error) error {
+	var operatorErr *OperatorError
+	if errors.As(err, &operatorErr) {
+		return err
+	}
+
+	path, _ := e.locate(node)
+
+	return &OperatorError{
+		Operator: operator,
+		Path:     path.Pointer(),
+		Err:      err,
+	}
+}
+
+// LazyOperatorFn defines the signature for custom operators that evaluate
+// their own arguments. It takes the arguments as rules, not yet evaluated, and
+// the Evaluator used to evaluate them on demand.
+type LazyOperatorFn func(args []any, ev Evaluator) (result any, err error)
+
+// Evaluator evaluates the arguments of a lazy operator.
+type Evaluator interface {
+	// Data returns the data the operation is evaluated against.
+	Data() any
+
+	// Evaluate applies the rule to the data of the operation.
+	Evaluate(rule any) (any, error)
+
+	// EvaluateWith applies the rule to other data, such as an element of an
+	// array. The data becomes a new scope, as the elements do in map: "var"
+	// reads from it and {"val": [[1], ...]} from the data of the operation.
+	EvaluateWith(rule, data any) (any, error)
+}
+
+// AddLazyOperator registers a custom operator with the given key and function.
+// Unlike AddOperator, the arguments are handed to the function as rules, so
+// it decides which of them to evaluate, against which data and how many times,
+// the way the built-in and, or and filter do. An argument that isn't an array
+// is handed as an array with a single rule. The errors it returns are wrapped
+// in an OperatorError, like the ones of AddOperatorWithError.
+//
+// Parameters:
+//   - key: the operator name to register (e.g., "first_match")
+//   - cb: the function to execute when the operator is encountered
+//
+// Concurrency: This function is safe for concurrent use as it properly locks the operators map.
+func AddLazyOperator(key string, cb LazyOperatorFn) {
+	operatorsLock.Lock()
+	defer operatorsLock.Unlock()
+
+	delete(signatures, key)
+
+	operators[key] = func(e *evaluator, values, data any) any {
+		node := e.node
+
+		args, ok := values.([]any)
+		if !ok {
+			args = []any{values}
+		}
+
+		result, err := cb(args, lazyEvaluator{e: e, data: data})
+		if err != nil {
+			panic(e.operatorError(key, node, err))
+		}
+
+		return result
 	}
 }
 
-func operation(operator string, values, data any) any {
+// lazyEvaluator implements Evaluator for an operation of a lazy operator.
+type lazyEvaluator struct {
+	e    *evaluator
+	data any
+}
+
+func (l lazyEvaluator) Data() any {
+	return l.data
+}
+
+func (l lazyEvaluator) Evaluate(rule any) (any, error) {
+	return l.e.guarded(rule, l.data)
+}
+
+func (l lazyEvaluator) EvaluateWith(rule, data any) (any, error) {
+	defer l.e.enterScope(nil, false)()
+	l.e.setElement(0, data)
+
+	return l.e.guarded(rule, data)
+}
+
+func (e *evaluator) operation(operator string, values, data any) any {
 	operatorsLock.RLock()
 	opFn, found := operators[operator]
 	operatorsLock.RUnlock()
 	if found {
-		return opFn(values, data)
+		return opFn(e, values, data)
 	}
 
 	panic(ErrInvalidOperator{
@@ -60,45 +212,48 @@ func init() {
 	operatorsLock.Lock()
 	defer operatorsLock.Unlock()
 
-	operators["and"] = _and
-	operators["or"] = _or
-	operators["filter"] = filter
-	operators["map"] = _map
-	operators["reduce"] = reduce
-	operators["all"] = all
-	operators["none"] = none
-	operators["some"] = some
-	operators["in"] = _in
-	operators["missing"] = missing
-	operators["missing_some"] = missingSome
-	operators["var"] = getVar
-	operators["set"] = setProperty
-	operators["cat"] = concat
-	operators["substr"] = substr
-	operators["merge"] = merge
-	operators["if"] = conditional
-	operators["?:"] = conditional
-	operators["max"] = max
-	operators["min"] = min
-	operators["+"] = sum
-	operators["-"] = minus
-	operators["*"] = mult
-	operators["/"] = div
-	operators["%"] = mod
-	operators["abs"] = abs
-	operators["!"] = negative
-	operators["!!"] = func(v, d any) any { return !javascript.IsTrue(negative(v, d)) }
-	operators["==="] = hardEquals
-	operators["!=="] = func(v, d any) any { return !hardEquals(v, d).(bool) }
-	operators["<"] = isLessThan
-	operators["<="] = isLessOrEqualThan
-	operators[">"] = isGreaterThan
-	operators[">="] = isGreaterOrEqualThan
-	operators["=="] = isEqual
-	operators["!="] = func(v, d any) any { return !isEqual(v, d).(bool) }
+	operators["and"] = (*evaluator)._and
+	operators["or"] = (*evaluator)._or
+	operators["filter"] = (*evaluator).filter
+	operators["map"] = (*evaluator)._map
+	operators["reduce"] = (*evaluator).reduce
+	operators["all"] = (*evaluator).all
+	operators["none"] = (*evaluator).none
+	operators["some"] = (*evaluator).some
+	operators["in"] = (*evaluator)._in
+	operators["missing"] = (*evaluator).missing
+	operators["missing_some"] = (*evaluator).missingSome
+	operators["var"] = (*evaluator).getVar
+	operators["val"] = (*evaluator).val
+	operators["let"] = (*evaluator).let
+	operators["try"] = (*evaluator).try
+	operators["set"] = (*evaluator).setProperty
+	operators["cat"] = (*evaluator).concat
+	operators["substr"] = (*evaluator).substr
+	operators["merge"] = (*evaluator).merge
+	operators["if"] = (*evaluator).conditional
+	operators["?:"] = (*evaluator).conditional
+	operators["max"] = (*evaluator).max
+	operators["min"] = (*evaluator).min
+	operators["+"] = (*evaluator).sum
+	operators["-"] = (*evaluator).minus
+	operators["*"] = (*evaluator).mult
+	operators["/"] = (*evaluator).div
+	operators["%"] = (*evaluator).mod
+	operators["abs"] = (*evaluator).abs
+	operators["!"] = (*evaluator).negative
+	operators["!!"] = func(e *evaluator, v, d any) any { return !javascript.IsTrue(e.negative(v, d)) }
+	operators["==="] = (*evaluator).hardEquals
+	operators["!=="] = func(e *evaluator, v, d any) any { return !e.hardEquals(v, d).(bool) }
+	operators["<"] = (*evaluator).isLessThan
+	operators["<="] = (*evaluator).isLessOrEqualThan
+	operators[">"] = (*evaluator).isGreaterThan
+	operators[">="] = (*evaluator).isGreaterOrEqualThan
+	operators["=="] = (*evaluator).isEqual
+	operators["!="] = func(e *evaluator, v, d any) any { return !e.isEqual(v, d).(bool) }
 
 	/* CUSTOM OPERATORS */
-	operators["contains_all"] = func(v, d any) any { return containsAll(parseValues(v, d), d) }
-	operators["contains_any"] = func(v, d any) any { return containsAny(parseValues(v, d), d) }
-	operators["contains_none"] = func(v, d any) any { return containsNone(parseValues(v, d), d) }
+	operators["contains_all"] = func(e *evaluator, v, d any) any { return containsAll(e.parseValues(v, d), d) }
+	operators["contains_any"] = func(e *evaluator, v, d any) any { return containsAny(e.parseValues(v, d), d) }
+	operators["contains_none"] = func(e *evaluator, v, d any) any { return containsNone(e.parseValues(v, d), d) }
 }
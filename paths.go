@@ -0,0 +1,284 @@
+package jsonlogic
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Path is a variable path, as the sequence of object keys and array indexes
+// it goes through.
+//
+// Variables accept three path syntaxes:
+//   - dotted strings, like "user.address.city", where each "." starts a new key
+//   - JSON Pointers (RFC 6901) like "/user/e-mail.address", for keys with dots
+//   - arrays of segments like ["user", "e-mail.address", 0], where strings are
+//     keys used as-is and numbers only match array indexes
+//
+// In dotted strings and JSON Pointers, a "*" segment matches all the values of
+// an object or array and a "**" segment matches any number of levels,
+// including none. Paths with these wildcards read the array of all the
+// non-null values they match. Arrays of segments have no wildcards, so keys
+// named "*" or "**" are read with them, like ["rates", "*"].
+type Path []string
+
+// pathSegment is a step of a path. Segments written as numbers in an array of
+// segments only match array indexes; every other segment matches object keys
+// and, when numeric, array indexes as well. Segments written as strings in an
+// array of segments are literal keys, never wildcards.
+type pathSegment struct {
+	key     string
+	isIndex bool
+	literal bool
+}
+
+// isWildcard reports whether the segment matches every value of an object or array.
+func (s pathSegment) isWildcard() bool {
+	return s.key == "*" && !s.isIndex && !s.literal
+}
+
+// isRecursive reports whether the segment matches any number of levels.
+func (s pathSegment) isRecursive() bool {
+	return s.key == "**" && !s.isIndex && !s.literal
+}
+
+// ParsePath parses a path written in any of the syntaxes accepted by variables.
+// It returns false when the value is not a path. The Path doesn't tell which
+// syntax it was written in, so the "*" and "**" keys of an array of segments
+// are reported by HasWildcard as well.
+func ParsePath(path any) (Path, bool) {
+	segments, ok := parsePath(path)
+	if !ok {
+		return nil, false
+	}
+
+	p := make(Path, 0, len(segments))
+	for _, segment := range segments {
+		p = append(p, segment.key)
+	}
+
+	return p, true
+}
+
+// HasWildcard reports whether the path contains a "*" or "**" segment.
+func (p Path) HasWildcard() bool {
+	for _, key := range p {
+		if key == "*" || key == "**" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// String returns the path in dotted notation, or as a JSON Pointer when a key
+// is empty or contains a dot, or when the first key starts with "/", so that
+// ParsePath reads it back.
+func (p Path) String() string {
+	if len(p) > 0 && strings.HasPrefix(p[0], "/") {
+		return p.Pointer()
+	}
+
+	for _, key := range p {
+		if key == "" || strings.Contains(key, ".") {
+			return p.Pointer()
+		}
+	}
+
+	return strings.Join(p, ".")
+}
+
+// Pointer returns the path as a JSON Pointer.
+func (p Path) Pointer() string {
+	var s strings.Builder
+
+	escaper := strings.NewReplacer("~", "~0", "/", "~1")
+	for _, key := range p {
+		s.WriteString("/")
+		s.WriteString(escaper.Replace(key))
+	}
+
+	return s.String()
+}
+
+func parsePath(path any) ([]pathSegment, bool) {
+	switch p := path.(type) {
+	case float64:
+		return parseDottedPath(toString(p)), true
+	case string:
+		if strings.HasPrefix(p, "/") {
+			return parsePointer(p), true
+		}
+		return parseDottedPath(p), true
+	case []any:
+		segments := make([]pathSegment, 0, len(p))
+		for _, segment := range p {
+			switch s := segment.(type) {
+			case string:
+				segments = append(segments, pathSegment{key: s, literal: true})
+			case float64:
+				segments = append(segments, pathSegment{key: toString(s), isIndex: true})
+			default:
+				return nil, false
+			}
+		}
+		return segments, true
+	}
+
+	return nil, false
+}
+
+func parseDottedPath(path string) []pathSegment {
+	parts := strings.Split(path, ".")
+	segments := make([]pathSegment, 0, len(parts))
+
+	for _, part := range parts {
+		if part == "" {
+			continue
+		}
+		segments = append(segments, pathSegment{key: part})
+	}
+
+	return segments
+}
+
+func parsePointer(pointer string) []pathSegment {
+	parts := strings.Split(pointer[1:], "/")
+	segments := make([]pathSegment, 0, len(parts))
+
+	unescaper := strings.NewReplacer("~1", "/", "~0", "~")
+	for _, part := range parts {
+		segments = append(segments, pathSegment{key: unescaper.Replace(part)})
+	}
+
+	return segments
+}
+
+// lookupPath follows the path from data. It returns false when the path
+// doesn't exist or leads to a null value. Paths with wildcards return the
+// array of the values they match, or false when they match nothing.
+func lookupPath(segments []pathSegment, data any) (any, bool) {
+	for _, segment := range segments {
+		if segment.isWildcard() || segment.isRecursive() {
+			matches := lookupWildcardPath(segments, data)
+			return matches, len(matches) > 0
+		}
+	}
+
+	if data == nil {
+		return nil, false
+	}
+
+	value := data
+
+	for _, segment := range segments {
+		var ok bool
+
+		value, ok = stepPath(value, segment)
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// lookupWildcardPath returns every non-null value matched by the path. Object
+// values are visited in the order of their keys.
+func lookupWildcardPath(segments []pathSegment, data any) []any {
+	matches := make([]any, 0)
+
+	var walk func(value any, rest []pathSegment)
+	walk = func(value any, rest []pathSegment) {
+		if value == nil {
+			return
+		}
+
+		if len(rest) == 0 {
+			matches = append(matches, value)
+			return
+		}
+
+		segment := rest[0]
+
+		switch {
+		case segment.isWildcard():
+			for _, child := range pathChildren(value) {
+				walk(child, rest[1:])
+			}
+		case segment.isRecursive():
+			walk(value, rest[1:])
+			for _, child := range pathChildren(value) {
+				walk(child, rest)
+			}
+		default:
+			if next, ok := stepPath(value, segment); ok {
+				walk(next, rest[1:])
+			}
+		}
+	}
+
+	walk(data, segments)
+
+	return matches
+}
+
+func stepPath(value any, segment pathSegment) (any, bool) {
+	switch v := value.(type) {
+	case map[string]any:
+		if segment.isIndex {
+			return nil, false
+		}
+		value = v[segment.key]
+	case []any:
+		f, err := strconv.ParseFloat(segment.key, 64)
+		if err != nil {
+			return nil, false
+		}
+		pos := int(f)
+		if pos < 0 || pos >= len(v) {
+			return nil, false
+		}
+		value = v[pos]
+	default:
+		return nil, false
+	}
+
+	return value, value != nil
+}
+
+func pathChildren(value any) []any {
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		children := make([]any, 0, len(v))
+		for _, key := range keys {
+			children = append(children, v[key])
+		}
+		return children
+	case []any:
+		return v
+	}
+
+	return nil
+}
+
+// readPath returns the value found at the path, or nil when there is none.
+func readPath(path, data any) any {
+	segments, ok := parsePath(path)
+	if !ok {
+		return nil
+	}
+
+	value, found := lookupPath(segments, data)
+	if !found {
+		return nil
+	}
+
+	return value
+}
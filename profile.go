@@ -0,0 +1,179 @@
+package jsonlogic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Profile selects how the evaluation handles the edge cases in which
+// implementations of JsonLogic disagree.
+//
+//	                              Legacy    JsonLogicJS        CommunitySpec
+//	cat trims the result          yes       no                 no
+//	cat of true, null and [1,2]   fails     "true", "", "1,2"  "true", "", "1,2"
+//	- and / without arguments     0         null               fail
+//	- of a single value           0         negated            negated
+//	reduce over null elements     skipped   evaluated          evaluated
+//	in with a non-string needle   fails     converted          converted
+//
+// Legacy, the zero value, keeps the behavior of the previous versions of this
+// package. JsonLogicJS follows json-logic-js, the reference implementation,
+// and CommunitySpec follows it except for raising errors where json-logic-js
+// returns NaN.
+type Profile int
+
+const (
+	// ProfileLegacy trims the result of cat and fails on the values it can't
+	// join, returns 0 for - and / without arguments and for - of a value
+	// written without an array, skips the null elements in reduce, and fails
+	// on in with a needle that isn't a string searched in a string.
+	ProfileLegacy Profile = iota
+
+	// ProfileJsonLogicJS keeps the whitespace of cat and joins the values
+	// like Array.prototype.join, returns null for the NaN of - and / without
+	// arguments, negates - of a single value, evaluates the null elements in
+	// reduce, and converts the needle of in to a string.
+	ProfileJsonLogicJS
+
+	// ProfileCommunitySpec is ProfileJsonLogicJS, except that - and /
+	// without arguments fail instead of returning null.
+	ProfileCommunitySpec
+)
+
+func (p Profile) String() string {
+	switch p {
+	case ProfileLegacy:
+		return "Legacy"
+	case ProfileJsonLogicJS:
+		return "JsonLogicJS"
+	case ProfileCommunitySpec:
+		return "CommunitySpec"
+	}
+
+	return fmt.Sprintf("Profile(%d)", int(p))
+}
+
+// Engine applies rules with a fixed set of options, so the options are chosen
+// once for every evaluation.
+type Engine struct {
+	opts Options
+}
+
+// NewEngine returns an Engine applying the rules with the given options.
+func NewEngine(opts Options) *Engine {
+	return &Engine{opts: opts}
+}
+
+// Options returns the options of the engine.
+func (en *Engine) Options() Options {
+	return en.opts
+}
+
+// Apply works like the package-level Apply, with the options of the engine.
+//
+// Parameters:
+//   - rule: io.Reader representing the transformation rule to be applied
+//   - data: io.Reader containing the input data to transform
+//   - result: io.Writer containing the transformed data
+//
+// Returns:
+//   - err: error if the transformation fails or if type assertions are invalid
+func (en *Engine) Apply(rule, data io.Reader, result io.Writer) error {
+	if data == nil {
+		data = strings.NewReader("{}")
+	}
+
+	var _rule any
+	var _data any
+
+	decoder := json.NewDecoder(rule)
+	err := decoder.Decode(&_rule)
+	if err != nil {
+		return err
+	}
+
+	decoder = json.NewDecoder(data)
+	err = decoder.Decode(&_data)
+	if err != nil {
+		return err
+	}
+
+	output, err := newEvaluatorWithOptions(en.opts).evaluate(_rule, _data)
+	if err != nil {
+		return err
+	}
+
+	return json.NewEncoder(result).Encode(output)
+}
+
+// Compile works like the package-level Compile, returning a rule applied with
+// the options of the engine.
+func (en *Engine) Compile(rule json.RawMessage) (*Rule, error) {
+	return CompileWithOptions(rule, en.opts)
+}
+
+// ApplyRaw works like the package-level ApplyRaw, with the options of the engine.
+//
+// Parameters:
+//   - rule: json.RawMessage representing the transformation rule to be applied
+//   - data: json.RawMessage containing the input data to transform
+//
+// Returns:
+//   - output: json.RawMessage containing the transformed data
+//   - err: error if the transformation fails or if type assertions are invalid
+func (en *Engine) ApplyRaw(rule, data json.RawMessage) (json.RawMessage, error) {
+	return ApplyRawWithOptions(rule, data, en.opts)
+}
+
+// ApplyInterface works like the package-level ApplyInterface, with the options of the engine.
+//
+// Parameters:
+//   - rule: interface{} representing the transformation rule to be applied
+//   - data: interface{} containing the input data to transform
+//
+// Returns:
+//   - output: interface{} containing the transformed data
+//   - err: error if unsupported types are detected or if the transformation fails
+func (en *Engine) ApplyInterface(rule, data any) (any, error) {
+	return ApplyInterfaceWithOptions(rule, data, en.opts)
+}
+
+// jsString converts the value to a string like String() in JavaScript.
+func jsString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		if v {
+			return "true"
+		}
+		return "false"
+	case float64:
+		return toString(v)
+	case string:
+		return v
+	case []any:
+		return jsJoin(v, ",")
+	}
+
+	return "[object Object]"
+}
+
+// jsJoin joins the values like Array.prototype.join in JavaScript, in which
+// null values become empty strings.
+func jsJoin(values []any, separator string) string {
+	var s strings.Builder
+
+	for i, value := range values {
+		if i > 0 {
+			s.WriteString(separator)
+		}
+		if value != nil {
+			s.WriteString(jsString(value))
+		}
+	}
+
+	return s.String()
+}
@@ -0,0 +1,96 @@
+package jsonlogic_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/jsonlogictest"
+)
+
+var profiles = map[string]jsonlogic.Profile{
+	"legacy.json":        jsonlogic.ProfileLegacy,
+	"jsonlogicjs.json":   jsonlogic.ProfileJsonLogicJS,
+	"communityspec.json": jsonlogic.ProfileCommunitySpec,
+}
+
+func TestProfileFixtures(t *testing.T) {
+	for file, profile := range profiles {
+		buffer, err := os.ReadFile(filepath.Join("testdata", "profiles", file))
+		if !assert.NoError(t, err) {
+			continue
+		}
+
+		var fixtures []struct {
+			Description string          `json:"description"`
+			Rule        json.RawMessage `json:"rule"`
+			Data        json.RawMessage `json:"data"`
+			Result      json.RawMessage `json:"result"`
+			Error       bool            `json:"error"`
+		}
+		if !assert.NoError(t, json.Unmarshal(buffer, &fixtures)) {
+			continue
+		}
+
+		engine := jsonlogic.NewEngine(jsonlogic.Options{Profile: profile})
+
+		for _, fixture := range fixtures {
+			t.Run(fmt.Sprintf("%s:%s", profile, fixture.Description), func(t *testing.T) {
+				output, err := engine.ApplyRaw(fixture.Rule, fixture.Data)
+				if fixture.Error {
+					assert.Error(t, err)
+					return
+				}
+
+				assert.NoError(t, err)
+				assert.JSONEq(t, string(fixture.Result), string(output))
+			})
+		}
+	}
+}
+
+func TestProfilesPassTheCommunitySuite(t *testing.T) {
+	suite, err := jsonlogictest.Community()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, profile := range profiles {
+		engine := jsonlogic.NewEngine(jsonlogic.Options{Profile: profile})
+
+		report := jsonlogictest.Run(suite, engine.ApplyInterface)
+		assert.True(t, report.OK(), "%s: %s", profile, report)
+	}
+}
+
+func TestProfileString(t *testing.T) {
+	assert.Equal(t, "Legacy", jsonlogic.ProfileLegacy.String())
+	assert.Equal(t, "JsonLogicJS", jsonlogic.ProfileJsonLogicJS.String())
+	assert.Equal(t, "CommunitySpec", jsonlogic.ProfileCommunitySpec.String())
+	assert.Equal(t, "Profile(7)", jsonlogic.Profile(7).String())
+}
+
+func TestProfileCanBeSelectedPerEvaluation(t *testing.T) {
+	rule := json.RawMessage(`{"cat": ["a ", "b "]}`)
+
+	output, err := jsonlogic.ApplyRaw(rule, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"a b"`, string(output))
+
+	output, err = jsonlogic.ApplyRawWithOptions(rule, nil, jsonlogic.Options{Profile: jsonlogic.ProfileJsonLogicJS})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"a b "`, string(output))
+
+	var result bytes.Buffer
+	engine := jsonlogic.NewEngine(jsonlogic.Options{Profile: jsonlogic.ProfileCommunitySpec, Strict: true})
+	assert.NoError(t, engine.Apply(strings.NewReader(string(rule)), nil, &result))
+	assert.JSONEq(t, `"a b "`, result.String())
+	assert.Equal(t, jsonlogic.Options{Profile: jsonlogic.ProfileCommunitySpec, Strict: true}, engine.Options())
+}
@@ -0,0 +1,216 @@
+package refactor
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/ast"
+)
+
+// InlineConstants replaces the variables reading constants by their values.
+// The constants are keyed by their paths, written in any of the syntaxes of
+// jsonlogic.Path, and the variables reading inside a constant are replaced by
+// the value found there: with {"limits": {"max": 10}}, {"var": "limits.max"}
+// becomes 10. The variables whose path isn't found are left as they are, and
+// the ones reading a null constant with a default value are replaced by it,
+// outside the bodies of the iterations, which read the element instead.
+//
+// The variables are found like in RenameVars. Objects with a single key can't
+// be written as literals, since they're operations, so the variables reading
+// one, or an array holding one, are left as they are: with
+// {"limits": {"max": {"value": 10}}}, {"var": "limits.max"} isn't replaced,
+// while {"var": "limits.max.value"} is.
+//
+// Parameters:
+//   - rule: interface{} representing the rule to be rewritten
+//   - constants: map of the paths of the constants to their values
+//
+// Returns:
+//   - result: the rule with the constants inlined
+//   - changes: the variables replaced, in order of appearance
+//   - err: error when the rule or a constant is invalid
+func InlineConstants(rule any, constants map[string]any) (any, []Change, error) {
+	type constant struct {
+		path  jsonlogic.Path
+		value any
+	}
+
+	keys := make([]string, 0, len(constants))
+	for key := range constants {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parsed := make([]constant, 0, len(constants))
+	for _, key := range keys {
+		p, ok := jsonlogic.ParsePath(key)
+		if !ok || len(p) == 0 {
+			return nil, nil, fmt.Errorf("refactor: invalid path %q", key)
+		}
+		if err := checkType(constants[key]); err != nil {
+			return nil, nil, fmt.Errorf("refactor: constant %q: %w", key, err)
+		}
+		parsed = append(parsed, constant{path: p, value: constants[key]})
+	}
+
+	// lookup returns the value read by the path from the longest constant it
+	// starts with.
+	lookup := func(path jsonlogic.Path) (any, bool) {
+		var longest *constant
+		for i, c := range parsed {
+			if hasPrefix(path, c.path) && (longest == nil || len(c.path) > len(longest.path)) {
+				longest = &parsed[i]
+			}
+		}
+		if longest == nil {
+			return nil, false
+		}
+		return descend(longest.value, path[len(longest.path):])
+	}
+
+	root, err := ast.Parse(rule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []Change
+
+	var inline func(n ast.Node, s scope) ast.Node
+	inline = func(n ast.Node, s scope) ast.Node {
+		var path jsonlogic.Path
+		var def ast.Node
+
+		switch n := n.(type) {
+		case *ast.Var:
+			p, ok := n.Path()
+			if !ok || len(p) == 0 || s.bound[p[0]] || !s.readsData(n.Name.Rule()) {
+				return n
+			}
+			path, def = p, n.Default
+		case *ast.Op:
+			if n.Operator != "val" {
+				return n
+			}
+			segments, bound, ok := valSegments(n, s)
+			if !ok {
+				return n
+			}
+			p, ok := jsonlogic.ParsePath(segments)
+			if !ok || bound[p[0]] {
+				return n
+			}
+			path = p
+		default:
+			return n
+		}
+
+		value, ok := lookup(path)
+		if !ok || path.HasWildcard() || !isLiteral(value) {
+			return n
+		}
+		if value == nil && s.depth > 0 {
+			// the element is read when the data holds null
+			return n
+		}
+
+		inlined := literal(value, n.Location())
+		if value == nil && def != nil {
+			inlined = def
+		}
+		changes = append(changes, Change{At: n.Location(), Before: n.Rule(), After: inlined.Rule()})
+
+		if inlined == def {
+			// the default may read constants as well
+			return transform(def, s, inline)
+		}
+		return inlined
+	}
+
+	result := transform(root, scope{}, inline)
+
+	return result.Rule(), changes, nil
+}
+
+// descend returns the value read by the path inside the value.
+func descend(value any, path jsonlogic.Path) (any, bool) {
+	for _, key := range path {
+		switch v := value.(type) {
+		case map[string]any:
+			item, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			value = item
+		case []any:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(v) {
+				return nil, false
+			}
+			value = v[i]
+		default:
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// checkType returns an error when the value isn't of a type decoded by
+// encoding/json.
+func checkType(value any) error {
+	switch v := value.(type) {
+	case nil, bool, float64, string:
+		return nil
+	case []any:
+		for _, item := range v {
+			if err := checkType(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		for _, item := range v {
+			if err := checkType(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	return fmt.Errorf("unsupported type %T", value)
+}
+
+// isLiteral tells whether the value is read as a literal in a rule: objects
+// with a single key are operations, inside arrays as well, while the values
+// of the other objects aren't evaluated.
+func isLiteral(value any) bool {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			if !isLiteral(item) {
+				return false
+			}
+		}
+	case map[string]any:
+		return len(v) != 1
+	}
+	return true
+}
+
+// literal returns the node of a value accepted by isLiteral.
+func literal(value any, at jsonlogic.Path) ast.Node {
+	switch v := value.(type) {
+	case []any:
+		items := make([]ast.Node, len(v))
+		for i, item := range v {
+			items[i] = literal(item, child(at, strconv.Itoa(i)))
+		}
+		return &ast.Array{At: at, Items: items}
+	case map[string]any:
+		return &ast.ObjectLiteral{At: at, Value: v}
+	}
+
+	return &ast.Literal{At: at, Value: value}
+}
@@ -0,0 +1,125 @@
+package refactor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/diegoholiveira/jsonlogic/v3/refactor"
+)
+
+func TestInlineConstants(t *testing.T) {
+	constants := map[string]any{
+		"limits":   map[string]any{"max": 10.0, "min": 1.0, "tiers": []any{"gold", "silver"}},
+		"currency": "BRL",
+		"discount": nil,
+	}
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+		changes  []string
+	}{
+		"constant": {
+			rule:     `{"==": [{"var": "currency"}, "BRL"]}`,
+			expected: `{"==": ["BRL", "BRL"]}`,
+			changes:  []string{`/==/0: {"var":"currency"} -> "BRL"`},
+		},
+		"inside a constant": {
+			rule:     `{"<=": [{"var": "limits.min"}, {"var": "qty"}, {"val": ["limits", "max"]}]}`,
+			expected: `{"<=": [1, {"var": "qty"}, 10]}`,
+			changes: []string{
+				`/<=/0: {"var":"limits.min"} -> 1`,
+				`/<=/2: {"val":["limits","max"]} -> 10`,
+			},
+		},
+		"array": {
+			rule:     `{"in": [{"var": "tier"}, {"var": "limits.tiers"}]}`,
+			expected: `{"in": [{"var": "tier"}, ["gold", "silver"]]}`,
+			changes:  []string{`/in/1: {"var":"limits.tiers"} -> ["gold","silver"]`},
+		},
+		"null with a default": {
+			rule:     `{"*": [{"var": "price"}, {"var": ["discount", {"var": "currency"}]}]}`,
+			expected: `{"*": [{"var": "price"}, "BRL"]}`,
+			changes: []string{
+				`/*/1: {"var":["discount",{"var":"currency"}]} -> {"var":"currency"}`,
+				`/*/1/var/1: {"var":"currency"} -> "BRL"`,
+			},
+		},
+		"not found": {
+			rule:     `{"var": "limits.avg"}`,
+			expected: `{"var": "limits.avg"}`,
+		},
+		"read before the element": {
+			rule:     `{"filter": [{"var": "items"}, {">=": [{"var": "qty"}, {"var": "limits.min"}]}]}`,
+			expected: `{"filter": [{"var": "items"}, {">=": [{"var": "qty"}, 1]}]}`,
+			changes:  []string{`/filter/1/>=/1: {"var":"limits.min"} -> 1`},
+		},
+		"null before the element": {
+			rule:     `{"some": [{"var": "items"}, {"var": ["discount", true]}]}`,
+			expected: `{"some": [{"var": "items"}, {"var": ["discount", true]}]}`,
+		},
+		"scopes": {
+			rule:     `{"let": [{"currency": "USD"}, {"map": [{"var": "items"}, {"cat": [{"var": "currency"}, {"val": [[1], "currency"]}]}]}]}`,
+			expected: `{"let": [{"currency": "USD"}, {"map": [{"var": "items"}, {"cat": [{"var": "currency"}, "BRL"]}]}]}`,
+			changes:  []string{`/let/1/map/1/cat/1: {"val":[[1],"currency"]} -> "BRL"`},
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			result, list, err := refactor.InlineConstants(decode(t, scenario.rule), constants)
+			require.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, encode(t, result))
+			assert.Equal(t, scenario.changes, changes(list))
+		})
+	}
+}
+
+func TestInlineConstantsSingleKeyObjects(t *testing.T) {
+	constants := map[string]any{
+		"limits": map[string]any{"max": 10.0},
+		"rules":  []any{map[string]any{"var": "a"}},
+		"labels": map[string]any{"en": map[string]any{"short": "hi"}, "pt": "oi"},
+	}
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+		changes  []string
+	}{
+		"inside": {
+			rule:     `{"var": "limits.max"}`,
+			expected: `10`,
+			changes:  []string{`(root): {"var":"limits.max"} -> 10`},
+		},
+		"object": {
+			rule:     `{"var": "limits"}`,
+			expected: `{"var": "limits"}`,
+		},
+		"array": {
+			rule:     `{"var": "rules"}`,
+			expected: `{"var": "rules"}`,
+		},
+		"inside an object with several keys": {
+			rule:     `{"var": "labels"}`,
+			expected: `{"en": {"short": "hi"}, "pt": "oi"}`,
+			changes:  []string{`(root): {"var":"labels"} -> {"en":{"short":"hi"},"pt":"oi"}`},
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			result, list, err := refactor.InlineConstants(decode(t, scenario.rule), constants)
+			require.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, encode(t, result))
+			assert.Equal(t, scenario.changes, changes(list))
+		})
+	}
+}
+
+func TestInlineConstantsErrors(t *testing.T) {
+	_, _, err := refactor.InlineConstants(decode(t, `{"var": "a"}`), map[string]any{"a": []int{1}})
+	assert.EqualError(t, err, `refactor: constant "a": unsupported type []int`)
+}
@@ -0,0 +1,306 @@
+// Package refactor rewrites rules, to follow changes of the data they read or
+// of the way they're written:
+//
+//	rule, changes, err := refactor.RenameVars(rule, map[string]string{
+//		"user.country": "profile.address.country",
+//	})
+//
+// The functions take rules as decoded by encoding/json and return the rule
+// rewritten, in the canonical form of the ast package, along with the list of
+// changes made.
+package refactor
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/ast"
+)
+
+// Change is an edit made to a rule.
+type Change struct {
+	// At is the location of the value changed in the rule given.
+	At     jsonlogic.Path
+	Before any
+	After  any
+}
+
+// String returns the change like `/and/0/==/0/var: "age" -> "user.age"`.
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", location(c.At), encode(c.Before), encode(c.After))
+}
+
+func encode(value any) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(encoded)
+}
+
+// RenameVars renames the variables read from the data, mapping paths to new
+// ones. The paths are written in any of the syntaxes of jsonlogic.Path, and
+// rename the paths starting with them as well: "user" renames "user.country".
+// When several paths match, the longest one is used.
+//
+// The paths read by "var", "val", "missing" and "missing_some" are renamed,
+// keeping the syntax they're written in, while the ones computed by other
+// operations are left as they are. Like in jsonlogic.Variables, the variables
+// of the bodies of map, filter, reduce, all, none and some read the elements
+// of an array rather than the data, and aren't renamed, except the "var"
+// operations reading the data before the element in the bodies of an
+// outermost filter, all, none or some. The variables reading the names bound
+// by "let" aren't renamed either.
+//
+// Parameters:
+//   - rule: interface{} representing the rule to be rewritten
+//   - renames: map of the paths to rename to their new paths
+//
+// Returns:
+//   - result: the rule with the paths renamed
+//   - changes: the paths renamed, in order of appearance
+//   - err: error when the rule or a path is invalid
+func RenameVars(rule any, renames map[string]string) (any, []Change, error) {
+	type rename struct {
+		from, to jsonlogic.Path
+	}
+
+	parsed := make([]rename, 0, len(renames))
+	for from, to := range renames {
+		f, ok := jsonlogic.ParsePath(from)
+		if !ok || len(f) == 0 {
+			return nil, nil, fmt.Errorf("refactor: invalid path %q", from)
+		}
+		t, ok := jsonlogic.ParsePath(to)
+		if !ok {
+			return nil, nil, fmt.Errorf("refactor: invalid path %q", to)
+		}
+		parsed = append(parsed, rename{from: f, to: t})
+	}
+
+	return RemapVars(rule, func(path jsonlogic.Path) (jsonlogic.Path, bool) {
+		var longest *rename
+		for i, r := range parsed {
+			if hasPrefix(path, r.from) && (longest == nil || len(r.from) > len(longest.from)) {
+				longest = &parsed[i]
+			}
+		}
+		if longest == nil {
+			return nil, false
+		}
+
+		remapped := append(jsonlogic.Path{}, longest.to...)
+		return append(remapped, path[len(longest.from):]...), true
+	})
+}
+
+// RemapVars is like RenameVars, with a function returning the new path of
+// each path read from the data, or false to keep it.
+func RemapVars(rule any, remap func(jsonlogic.Path) (jsonlogic.Path, bool)) (any, []Change, error) {
+	root, err := ast.Parse(rule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []Change
+
+	// rename returns the path written as value remapped, in the same syntax.
+	rename := func(value any, at jsonlogic.Path, bound map[string]bool) (ast.Node, bool) {
+		path, ok := jsonlogic.ParsePath(value)
+		if !ok || len(path) == 0 || bound[path[0]] {
+			return nil, false
+		}
+
+		remapped, ok := remap(path)
+		if !ok || equal(path, remapped) {
+			return nil, false
+		}
+
+		written := format(value, path, remapped)
+		changes = append(changes, Change{At: at, Before: value, After: written})
+
+		return pathNode(written, at), true
+	}
+
+	result := transform(root, scope{}, func(n ast.Node, s scope) ast.Node {
+		switch n := n.(type) {
+		case *ast.Var:
+			if !isPath(n.Name) || !s.readsData(n.Name.Rule()) {
+				break
+			}
+			if name, ok := rename(n.Name.Rule(), n.Name.Location(), s.bound); ok {
+				n.Name = name
+			}
+		case *ast.Op:
+			switch {
+			case n.Operator == "val":
+				renameVal(n, s, rename)
+			case n.Operator == "missing" && s.depth == 0:
+				for i, arg := range n.Args {
+					if !isPath(arg) {
+						continue
+					}
+					if name, ok := rename(arg.Rule(), arg.Location(), nil); ok {
+						n.Args[i] = name
+					}
+				}
+			case n.Operator == "missing_some" && s.depth == 0 && len(n.Args) == 2:
+				original, ok := n.Args[1].(*ast.Array)
+				if !ok {
+					break
+				}
+				list := &ast.Array{At: original.At, Items: append([]ast.Node(nil), original.Items...)}
+				n.Args[1] = list
+				for i, item := range original.Items {
+					if !isPath(item) {
+						continue
+					}
+					if name, ok := rename(item.Rule(), item.Location(), nil); ok {
+						list.Items[i] = name
+					}
+				}
+			}
+		}
+		return n
+	})
+
+	return result.Rule(), changes, nil
+}
+
+// renameVal renames the segments of a "val" reading the data.
+func renameVal(n *ast.Op, s scope, rename func(any, jsonlogic.Path, map[string]bool) (ast.Node, bool)) {
+	segments, bound, ok := valSegments(n, s)
+	if !ok {
+		return
+	}
+
+	renamed, ok := rename(segments, child(n.At, n.Operator), bound)
+	if !ok {
+		return
+	}
+
+	selector := len(n.Args) - len(segments)
+	n.Args = append(n.Args[:selector:selector], renamed.(*ast.Array).Items...)
+	n.Unwrapped = n.Unwrapped && len(n.Args) == 1
+}
+
+// valSegments returns the literal segments of a "val" reading the data, and
+// the names bound it may read. Its first argument selects an enclosing scope
+// when it's an array of a number, like in {"val": [[1], "limit"]}, and the
+// names bound by "let" are only read without a scope.
+func valSegments(n *ast.Op, s scope) ([]any, map[string]bool, bool) {
+	args, level, bound := n.Args, 0, s.bound
+	if len(args) > 0 {
+		if selector, ok := args[0].(*ast.Array); ok && len(selector.Items) == 1 {
+			if l, ok := selector.Items[0].(*ast.Literal); ok {
+				if number, ok := l.Value.(float64); ok {
+					args, level, bound = args[1:], int(number), nil
+				}
+			}
+		}
+	}
+
+	if level != s.depth || len(args) == 0 {
+		return nil, nil, false
+	}
+
+	segments := make([]any, len(args))
+	for i, arg := range args {
+		if _, ok := arg.(*ast.Literal); !ok {
+			return nil, nil, false
+		}
+		segments[i] = arg.Rule()
+	}
+
+	return segments, bound, true
+}
+
+// isPath tells whether the node may be a literal path: a string, a number or
+// an array of them.
+func isPath(n ast.Node) bool {
+	switch n := n.(type) {
+	case *ast.Literal:
+		return true
+	case *ast.Array:
+		for _, item := range n.Items {
+			if _, ok := item.(*ast.Literal); !ok {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// format writes the path in the syntax of the original value: the segments
+// shared with the end of an array of segments are kept as they were written.
+func format(original any, before, after jsonlogic.Path) any {
+	switch v := original.(type) {
+	case string:
+		if strings.HasPrefix(v, "/") {
+			return after.Pointer()
+		}
+	case []any:
+		shared := 0
+		for shared < len(before) && shared < len(after) && before[len(before)-1-shared] == after[len(after)-1-shared] {
+			shared++
+		}
+
+		segments := make([]any, len(after))
+		for i, key := range after {
+			segments[i] = key
+			if i >= len(after)-shared {
+				segments[i] = v[i-len(after)+len(before)]
+			}
+		}
+		return segments
+	}
+
+	return after.String()
+}
+
+// pathNode returns the node of a path written as a string or an array.
+func pathNode(value any, at jsonlogic.Path) ast.Node {
+	segments, ok := value.([]any)
+	if !ok {
+		return &ast.Literal{At: at, Value: value}
+	}
+
+	items := make([]ast.Node, len(segments))
+	for i, segment := range segments {
+		items[i] = &ast.Literal{At: child(at, strconv.Itoa(i)), Value: segment}
+	}
+	return &ast.Array{At: at, Items: items}
+}
+
+func hasPrefix(path, prefix jsonlogic.Path) bool {
+	return len(path) >= len(prefix) && equal(path[:len(prefix)], prefix)
+}
+
+func equal(a, b jsonlogic.Path) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// child returns a copy of the path extended with the keys.
+func child(path jsonlogic.Path, keys ...string) jsonlogic.Path {
+	return append(path[:len(path):len(path)], keys...)
+}
+
+// location returns the path as a JSON Pointer, or "(root)" when it's empty.
+func location(p jsonlogic.Path) string {
+	if len(p) == 0 {
+		return "(root)"
+	}
+	return p.Pointer()
+}
@@ -0,0 +1,195 @@
+package refactor_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/refactor"
+)
+
+func decode(t *testing.T, s string) any {
+	t.Helper()
+
+	var value any
+	require.NoError(t, json.Unmarshal([]byte(s), &value))
+
+	return value
+}
+
+func encode(t *testing.T, value any) string {
+	t.Helper()
+
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+
+	return string(encoded)
+}
+
+func changes(list []refactor.Change) []string {
+	var descriptions []string
+	for _, c := range list {
+		descriptions = append(descriptions, c.String())
+	}
+	return descriptions
+}
+
+func TestRenameVars(t *testing.T) {
+	scenarios := map[string]struct {
+		rule     string
+		renames  map[string]string
+		expected string
+		changes  []string
+	}{
+		"dotted": {
+			rule:     `{"==": [{"var": "user.country"}, "BR"]}`,
+			renames:  map[string]string{"user.country": "profile.address.country"},
+			expected: `{"==": [{"var": "profile.address.country"}, "BR"]}`,
+			changes:  []string{`/==/0/var: "user.country" -> "profile.address.country"`},
+		},
+		"prefix": {
+			rule:     `{"and": [{"var": "user.country.code"}, {"var": ["user", "none"]}, {"var": "username"}]}`,
+			renames:  map[string]string{"user": "account"},
+			expected: `{"and": [{"var": "account.country.code"}, {"var": ["account", "none"]}, {"var": "username"}]}`,
+			changes: []string{
+				`/and/0/var: "user.country.code" -> "account.country.code"`,
+				`/and/1/var/0: "user" -> "account"`,
+			},
+		},
+		"longest prefix": {
+			rule:     `{"cat": [{"var": "user.name"}, {"var": "user.age"}]}`,
+			renames:  map[string]string{"user": "account", "user.name": "profile.name"},
+			expected: `{"cat": [{"var": "profile.name"}, {"var": "account.age"}]}`,
+			changes: []string{
+				`/cat/0/var: "user.name" -> "profile.name"`,
+				`/cat/1/var: "user.age" -> "account.age"`,
+			},
+		},
+		"syntaxes": {
+			rule:     `{"and": [{"var": "/user/e-mail.address"}, {"var": [["user", "tags", 0]]}, {"val": ["user", "age"]}, {"val": "user"}]}`,
+			renames:  map[string]string{"user": "/account/v1.0"},
+			expected: `{"and": [{"var": "/account/v1.0/e-mail.address"}, {"var": [["account", "v1.0", "tags", 0]]}, {"val": ["account", "v1.0", "age"]}, {"val": ["account", "v1.0"]}]}`,
+			changes: []string{
+				`/and/0/var: "/user/e-mail.address" -> "/account/v1.0/e-mail.address"`,
+				`/and/1/var/0: ["user","tags",0] -> ["account","v1.0","tags",0]`,
+				`/and/2/val: ["user","age"] -> ["account","v1.0","age"]`,
+				`/and/3/val: ["user"] -> ["account","v1.0"]`,
+			},
+		},
+		"missing": {
+			rule:     `{"or": [{"missing": ["user.name", "user.age"]}, {"missing": "user.email"}, {"missing_some": [1, ["user.phone", "fax"]]}]}`,
+			renames:  map[string]string{"user": "account"},
+			expected: `{"or": [{"missing": ["account.name", "account.age"]}, {"missing": "account.email"}, {"missing_some": [1, ["account.phone", "fax"]]}]}`,
+			changes: []string{
+				`/or/0/missing/0: "user.name" -> "account.name"`,
+				`/or/0/missing/1: "user.age" -> "account.age"`,
+				`/or/1/missing: "user.email" -> "account.email"`,
+				`/or/2/missing_some/1/0: "user.phone" -> "account.phone"`,
+			},
+		},
+		"default": {
+			rule:     `{"var": ["user.age", {"var": "user.birth"}]}`,
+			renames:  map[string]string{"user": "account"},
+			expected: `{"var": ["account.age", {"var": "account.birth"}]}`,
+			changes: []string{
+				`/var/0: "user.age" -> "account.age"`,
+				`/var/1/var: "user.birth" -> "account.birth"`,
+			},
+		},
+		"iterations": {
+			rule:     `{"map": [{"var": "user.orders"}, {"and": [{"var": "user"}, {"val": [[1], "user", "vip"]}, {"missing": ["user"]}]}]}`,
+			renames:  map[string]string{"user": "account"},
+			expected: `{"map": [{"var": "account.orders"}, {"and": [{"var": "user"}, {"val": [[1], "account", "vip"]}, {"missing": ["user"]}]}]}`,
+			changes: []string{
+				`/map/0/var: "user.orders" -> "account.orders"`,
+				`/map/1/and/1/val: ["user","vip"] -> ["account","vip"]`,
+			},
+		},
+		"var with too many arguments": {
+			rule:     `{"var": ["user.name", "ignored", {"var": "user.id"}]}`,
+			renames:  map[string]string{"user": "account"},
+			expected: `{"var": ["account.name", "ignored", {"var": "account.id"}]}`,
+			changes: []string{
+				`/var/0: "user.name" -> "account.name"`,
+				`/var/2/var: "user.id" -> "account.id"`,
+			},
+		},
+		"read before the element": {
+			rule:     `{"all": [{"var": "items"}, {">=": [{"var": ""}, {"var": "user.min"}, {"missing": ["user"]}]}]}`,
+			renames:  map[string]string{"user.min": "profile.min", "user": "account"},
+			expected: `{"all": [{"var": "items"}, {">=": [{"var": ""}, {"var": "profile.min"}, {"missing": ["user"]}]}]}`,
+			changes:  []string{`/all/1/>=/1/var: "user.min" -> "profile.min"`},
+		},
+		"let": {
+			rule:     `{"let": [{"user": {"var": "user"}}, {"var": "user.name"}]}`,
+			renames:  map[string]string{"user": "account"},
+			expected: `{"let": [{"user": {"var": "account"}}, {"var": "user.name"}]}`,
+			changes:  []string{`/let/0/user/var: "user" -> "account"`},
+		},
+		"computed paths": {
+			rule:     `{"var": {"cat": ["user.", {"var": "field"}]}}`,
+			renames:  map[string]string{"user": "account"},
+			expected: `{"var": {"cat": ["user.", {"var": "field"}]}}`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			result, list, err := refactor.RenameVars(decode(t, scenario.rule), scenario.renames)
+			require.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, encode(t, result))
+			assert.Equal(t, scenario.changes, changes(list))
+		})
+	}
+}
+
+func TestRenameVarsKeepsTheRule(t *testing.T) {
+	rule := decode(t, `{"missing_some": [1, ["user.phone"]]}`)
+
+	_, _, err := refactor.RenameVars(rule, map[string]string{"user": "account"})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"missing_some": [1, ["user.phone"]]}`, encode(t, rule))
+}
+
+func TestRenameVarsEvaluatesTheSame(t *testing.T) {
+	rule := decode(t, `{"if": [{"missing": ["user.age"]}, "unknown", {">=": [{"var": "user.age"}, 18]}]}`)
+
+	result, _, err := refactor.RenameVars(rule, map[string]string{"user": "profile.person"})
+	require.NoError(t, err)
+
+	before, err := jsonlogic.ApplyInterface(rule, decode(t, `{"user": {"age": 20}}`))
+	require.NoError(t, err)
+	after, err := jsonlogic.ApplyInterface(result, decode(t, `{"profile": {"person": {"age": 20}}}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, true, before)
+	assert.Equal(t, before, after)
+}
+
+func TestRenameVarsErrors(t *testing.T) {
+	_, _, err := refactor.RenameVars(decode(t, `{"var": "a"}`), map[string]string{"": "b"})
+	assert.EqualError(t, err, `refactor: invalid path ""`)
+
+	_, _, err = refactor.RenameVars(map[string]any{"var": 1}, map[string]string{"a": "b"})
+	assert.EqualError(t, err, "ast: /var: unsupported type int")
+}
+
+func TestRemapVars(t *testing.T) {
+	rule := decode(t, `{"+": [{"var": "items.0.price"}, {"var": "items.1.price"}]}`)
+
+	result, list, err := refactor.RemapVars(rule, func(path jsonlogic.Path) (jsonlogic.Path, bool) {
+		if len(path) == 3 && path[0] == "items" {
+			return jsonlogic.Path{"prices", path[1]}, true
+		}
+		return nil, false
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{"+": [{"var": "prices.0"}, {"var": "prices.1"}]}`, encode(t, result))
+	assert.Len(t, list, 2)
+	assert.Equal(t, jsonlogic.Path{"+", "1", "var"}, list[1].At)
+}
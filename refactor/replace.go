@@ -0,0 +1,169 @@
+package refactor
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/diegoholiveira/jsonlogic/v3/ast"
+)
+
+// Replace replaces the sub-expressions of the rule matching the pattern by
+// the replacement. In the pattern, {"$": "name"} matches any sub-expression,
+// which replaces {"$": "name"} in the replacement; a name used more than once
+// in the pattern matches equal sub-expressions only:
+//
+//	// {"!": [{"!": [x]}]} becomes {"!!": [x]}
+//	refactor.Replace(rule,
+//		map[string]any{"!": []any{map[string]any{"!": []any{map[string]any{"$": "x"}}}}},
+//		map[string]any{"!!": []any{map[string]any{"$": "x"}}},
+//	)
+//
+// The sub-expressions are compared in the canonical form of the ast package,
+// where a single argument written without an array, like in {"!": {"var": "a"}},
+// differs from one in an array: the operators read an array result of the
+// first as their list of arguments, so the pattern above doesn't match
+// {"!": {"!": x}}, which needs a pattern written the same way. They're
+// replaced from the leaves up, so an expression holding a match is
+// matched after the replacement. The replacements are not matched again.
+//
+// Parameters:
+//   - rule: interface{} representing the rule to be rewritten
+//   - pattern: interface{} representing the sub-expressions to replace
+//   - replacement: interface{} representing what replaces them
+//
+// Returns:
+//   - result: the rule with the sub-expressions replaced
+//   - changes: the sub-expressions replaced, from the leaves up
+//   - err: error when a rule is invalid, or the replacement uses a name the pattern doesn't capture
+func Replace(rule, pattern, replacement any) (any, []Change, error) {
+	root, err := ast.Parse(rule)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	p, err := ast.Parse(pattern)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refactor: pattern: %w", err)
+	}
+
+	r, err := ast.Parse(replacement)
+	if err != nil {
+		return nil, nil, fmt.Errorf("refactor: replacement: %w", err)
+	}
+
+	captured := make(map[string]bool)
+	ast.Inspect(p, func(n ast.Node) bool {
+		if name, ok := placeholder(n); ok {
+			captured[name] = true
+		}
+		return true
+	})
+
+	var missing error
+	ast.Inspect(r, func(n ast.Node) bool {
+		if name, ok := placeholder(n); ok && !captured[name] && missing == nil {
+			missing = fmt.Errorf("refactor: the replacement uses %q, not captured by the pattern", name)
+		}
+		return true
+	})
+	if missing != nil {
+		return nil, nil, missing
+	}
+
+	var changes []Change
+
+	result := ast.Rewrite(root, func(n ast.Node) ast.Node {
+		captures := make(map[string]ast.Node)
+		if !match(p, n, captures) {
+			return n
+		}
+
+		replaced := ast.Rewrite(r, func(c ast.Node) ast.Node {
+			if name, ok := placeholder(c); ok {
+				return captures[name]
+			}
+			return c
+		})
+		changes = append(changes, Change{At: n.Location(), Before: n.Rule(), After: replaced.Rule()})
+
+		return replaced
+	})
+
+	return result.Rule(), changes, nil
+}
+
+// placeholder returns the name of a {"$": "name"} node.
+func placeholder(n ast.Node) (string, bool) {
+	op, ok := n.(*ast.Op)
+	if !ok || op.Operator != "$" || len(op.Args) != 1 {
+		return "", false
+	}
+
+	literal, ok := op.Args[0].(*ast.Literal)
+	if !ok {
+		return "", false
+	}
+
+	name, ok := literal.Value.(string)
+	return name, ok
+}
+
+// match tells whether the node matches the pattern, recording the
+// sub-expressions matched by the placeholders.
+func match(pattern, n ast.Node, captures map[string]ast.Node) bool {
+	if pattern == nil || n == nil {
+		return pattern == nil && n == nil
+	}
+
+	if name, ok := placeholder(pattern); ok {
+		if previous, ok := captures[name]; ok {
+			return reflect.DeepEqual(previous.Rule(), n.Rule())
+		}
+		captures[name] = n
+		return true
+	}
+
+	switch p := pattern.(type) {
+	case *ast.Literal:
+		l, ok := n.(*ast.Literal)
+		return ok && p.Value == l.Value
+	case *ast.ObjectLiteral:
+		o, ok := n.(*ast.ObjectLiteral)
+		return ok && reflect.DeepEqual(p.Rule(), o.Rule())
+	case *ast.Array:
+		a, ok := n.(*ast.Array)
+		return ok && matchAll(p.Items, a.Items, captures)
+	case *ast.Var:
+		v, ok := n.(*ast.Var)
+		return ok && match(p.Name, v.Name, captures) && match(p.Default, v.Default, captures) && matchAll(p.Extra, v.Extra, captures)
+	case *ast.Let:
+		l, ok := n.(*ast.Let)
+		if !ok || len(p.Bindings) != len(l.Bindings) {
+			return false
+		}
+		for _, name := range p.Names() {
+			binding, ok := l.Bindings[name]
+			if !ok || !match(p.Bindings[name], binding, captures) {
+				return false
+			}
+		}
+		return match(p.Body, l.Body, captures)
+	case *ast.Op:
+		o, ok := n.(*ast.Op)
+		return ok && p.Operator == o.Operator && p.Unwrapped == o.Unwrapped && matchAll(p.Args, o.Args, captures)
+	}
+
+	return false
+}
+
+func matchAll(patterns, nodes []ast.Node, captures map[string]ast.Node) bool {
+	if len(patterns) != len(nodes) {
+		return false
+	}
+	for i := range patterns {
+		if !match(patterns[i], nodes[i], captures) {
+			return false
+		}
+	}
+	return true
+}
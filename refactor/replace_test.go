@@ -0,0 +1,82 @@
+package refactor_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/diegoholiveira/jsonlogic/v3/refactor"
+)
+
+func TestReplace(t *testing.T) {
+	scenarios := map[string]struct {
+		rule        string
+		pattern     string
+		replacement string
+		expected    string
+		changes     []string
+	}{
+		"double negation": {
+			rule:        `{"or": [{"!": [{"!": [{"var": "a"}]}]}, {"!": [{"var": "b"}]}]}`,
+			pattern:     `{"!": [{"!": [{"$": "x"}]}]}`,
+			replacement: `{"!!": [{"$": "x"}]}`,
+			expected:    `{"or": [{"!!": [{"var": "a"}]}, {"!": [{"var": "b"}]}]}`,
+			changes:     []string{`/or/0: {"!":[{"!":[{"var":"a"}]}]} -> {"!!":[{"var":"a"}]}`},
+		},
+		"single argument without an array": {
+			rule:        `{"and": [{"!": {"!": {"var": "a"}}}, {"!": [{"!": [{"var": "b"}]}]}]}`,
+			pattern:     `{"!": {"!": {"$": "x"}}}`,
+			replacement: `{"!!": [{"$": "x"}]}`,
+			expected:    `{"and": [{"!!": [{"var": "a"}]}, {"!": [{"!": [{"var": "b"}]}]}]}`,
+			changes:     []string{`/and/0: {"!":{"!":{"var":"a"}}} -> {"!!":[{"var":"a"}]}`},
+		},
+		"from the leaves up": {
+			rule:        `{"+": [{"+": [1, 2]}, 3]}`,
+			pattern:     `{"+": [{"$": "a"}, {"$": "b"}]}`,
+			replacement: `{"sum": [{"$": "a"}, {"$": "b"}]}`,
+			expected:    `{"sum": [{"sum": [1, 2]}, 3]}`,
+			changes: []string{
+				`/+/0: {"+":[1,2]} -> {"sum":[1,2]}`,
+				`(root): {"+":[{"sum":[1,2]},3]} -> {"sum":[{"sum":[1,2]},3]}`,
+			},
+		},
+		"repeated name": {
+			rule:        `{"and": [{"==": [{"var": "a"}, {"var": "a"}]}, {"==": [{"var": "a"}, {"var": "b"}]}]}`,
+			pattern:     `{"==": [{"$": "x"}, {"$": "x"}]}`,
+			replacement: `true`,
+			expected:    `{"and": [true, {"==": [{"var": "a"}, {"var": "b"}]}]}`,
+			changes:     []string{`/and/0: {"==":[{"var":"a"},{"var":"a"}]} -> true`},
+		},
+		"canonical form": {
+			rule:        `{"if": [{"var": ["legacy"]}, 1, 0]}`,
+			pattern:     `{"var": "legacy"}`,
+			replacement: `{"var": "flags.legacy"}`,
+			expected:    `{"if": [{"var": "flags.legacy"}, 1, 0]}`,
+			changes:     []string{`/if/0: {"var":"legacy"} -> {"var":"flags.legacy"}`},
+		},
+		"no match": {
+			rule:        `{"merge": {"var": "lists"}}`,
+			pattern:     `{"merge": [{"$": "x"}]}`,
+			replacement: `{"$": "x"}`,
+			expected:    `{"merge": {"var": "lists"}}`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			result, list, err := refactor.Replace(decode(t, scenario.rule), decode(t, scenario.pattern), decode(t, scenario.replacement))
+			require.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, encode(t, result))
+			assert.Equal(t, scenario.changes, changes(list))
+		})
+	}
+}
+
+func TestReplaceErrors(t *testing.T) {
+	_, _, err := refactor.Replace(decode(t, `{"var": "a"}`), decode(t, `{"var": {"$": "x"}}`), decode(t, `{"$": "y"}`))
+	assert.EqualError(t, err, `refactor: the replacement uses "y", not captured by the pattern`)
+
+	_, _, err = refactor.Replace(decode(t, `{"var": "a"}`), map[string]any{"var": 1}, nil)
+	assert.EqualError(t, err, "refactor: pattern: ast: /var: unsupported type int")
+}
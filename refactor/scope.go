@@ -0,0 +1,110 @@
+package refactor
+
+import (
+	"github.com/diegoholiveira/jsonlogic/v3/ast"
+)
+
+// iterators evaluate their second argument once per element of their first.
+var iterators = map[string]bool{
+	"map": true, "filter": true, "reduce": true, "all": true, "none": true, "some": true,
+}
+
+// outerFirst are the iterators whose bodies look "var" up in the enclosing
+// data before the element.
+var outerFirst = map[string]bool{
+	"filter": true, "all": true, "none": true, "some": true,
+}
+
+// scope is the context of a node: the number of iterations around it, whose
+// bodies read the elements of arrays, whether "var" reads the data before
+// the element, like in the bodies of an outermost filter, all, none or some,
+// and the names bound by "let".
+type scope struct {
+	depth int
+	outer bool
+	bound map[string]bool
+}
+
+// readsData tells whether a "var" of the path reads the data: outside the
+// iterations, or before the element when the path isn't empty and doesn't
+// start with ".".
+func (s scope) readsData(name any) bool {
+	if s.depth == 0 {
+		return true
+	}
+	path, ok := name.(string)
+	return s.outer && (!ok || (path != "" && path[0] != '.'))
+}
+
+// transform returns a copy of the tree where every node is replaced by the
+// result of f, from the root down. f receives a copy of the node, with its own
+// lists of children, and the children of the node it returns are transformed
+// next.
+func transform(node ast.Node, s scope, f func(ast.Node, scope) ast.Node) ast.Node {
+	if node == nil {
+		return nil
+	}
+
+	switch n := node.(type) {
+	case *ast.Literal:
+		c := *n
+		node = f(&c, s)
+	case *ast.ObjectLiteral:
+		c := *n
+		node = f(&c, s)
+	case *ast.Array:
+		c := *n
+		c.Items = append([]ast.Node(nil), n.Items...)
+		node = f(&c, s)
+	case *ast.Var:
+		c := *n
+		c.Extra = append([]ast.Node(nil), n.Extra...)
+		node = f(&c, s)
+	case *ast.Let:
+		c := *n
+		c.Bindings = make(map[string]ast.Node, len(n.Bindings))
+		for name, binding := range n.Bindings {
+			c.Bindings[name] = binding
+		}
+		node = f(&c, s)
+	case *ast.Op:
+		c := *n
+		c.Args = append([]ast.Node(nil), n.Args...)
+		node = f(&c, s)
+	}
+
+	switch n := node.(type) {
+	case *ast.Array:
+		for i, item := range n.Items {
+			n.Items[i] = transform(item, s, f)
+		}
+	case *ast.Var:
+		n.Name = transform(n.Name, s, f)
+		n.Default = transform(n.Default, s, f)
+		for i, extra := range n.Extra {
+			n.Extra[i] = transform(extra, s, f)
+		}
+	case *ast.Let:
+		inner := scope{depth: s.depth, outer: s.outer, bound: make(map[string]bool, len(s.bound)+len(n.Bindings))}
+		for name := range s.bound {
+			inner.bound[name] = true
+		}
+		for _, name := range n.Names() {
+			n.Bindings[name] = transform(n.Bindings[name], s, f)
+			inner.bound[name] = true
+		}
+		n.Body = transform(n.Body, inner, f)
+	case *ast.Op:
+		for i, arg := range n.Args {
+			if iterators[n.Operator] && !n.Unwrapped && i == 1 {
+				inner := scope{depth: s.depth + 1, outer: s.outer || (s.depth == 0 && outerFirst[n.Operator]), bound: s.bound}
+				arg = transform(arg, inner, f)
+			} else {
+				arg = transform(arg, s, f)
+			}
+			n.Args[i] = arg
+		}
+	}
+
+	return node
+}
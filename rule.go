@@ -0,0 +1,120 @@
+package jsonlogic
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// ErrInvalidRule is returned when a rule is not valid JSON Logic.
+var ErrInvalidRule = errors.New("jsonlogic: invalid rule")
+
+// Rule is a JSON Logic rule that was decoded and validated once so it can be
+// applied to many data documents.
+type Rule struct {
+	logic any
+	opts  Options
+}
+
+// Compile decodes and validates a rule.
+//
+// Parameters:
+//   - rule: json.RawMessage containing the JSON Logic rule
+//
+// Returns:
+//   - *Rule: the compiled rule
+//   - error: error if the rule can't be decoded or is not valid JSON Logic
+func Compile(rule json.RawMessage) (*Rule, error) {
+	var _rule any
+
+	err := json.Unmarshal(rule, &_rule)
+	if err != nil {
+		return nil, err
+	}
+
+	return CompileInterface(_rule)
+}
+
+// CompileWithOptions works like Compile, returning a rule applied with the
+// given options.
+//
+// Parameters:
+//   - rule: json.RawMessage containing the JSON Logic rule
+//   - opts: settings of the evaluations of the rule
+//
+// Returns:
+//   - *Rule: the compiled rule
+//   - error: error if the rule can't be decoded or is not valid JSON Logic
+func CompileWithOptions(rule json.RawMessage, opts Options) (*Rule, error) {
+	compiled, err := Compile(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled.opts = opts
+	return compiled, nil
+}
+
+// CompileInterface validates a rule that was already decoded. Like in
+// ApplyInterface, the rule must contain only JSON-compatible types.
+func CompileInterface(rule any) (*Rule, error) {
+	if err := scanForUnsupportedTypes(rule); err != nil {
+		return nil, err
+	}
+
+	if !ValidateJsonLogic(rule) {
+		return nil, ErrInvalidRule
+	}
+
+	return &Rule{logic: rule}, nil
+}
+
+// CompileInterfaceWithOptions works like CompileInterface, returning a rule
+// applied with the given options.
+func CompileInterfaceWithOptions(rule any, opts Options) (*Rule, error) {
+	compiled, err := CompileInterface(rule)
+	if err != nil {
+		return nil, err
+	}
+
+	compiled.opts = opts
+	return compiled, nil
+}
+
+// Logic returns the decoded rule.
+func (r *Rule) Logic() any {
+	return r.logic
+}
+
+// Options returns the options the rule is applied with.
+func (r *Rule) Options() Options {
+	return r.opts
+}
+
+// Apply applies the rule to data containing only JSON-compatible types.
+func (r *Rule) Apply(data any) (any, error) {
+	if err := scanForUnsupportedTypes(data); err != nil {
+		return nil, err
+	}
+	return newEvaluatorWithOptions(r.opts).evaluate(r.logic, data)
+}
+
+// ApplyRaw decodes the data, applies the rule to it and encodes the result.
+func (r *Rule) ApplyRaw(data json.RawMessage) (json.RawMessage, error) {
+	if data == nil {
+		data = json.RawMessage("{}")
+	}
+
+	var _data any
+
+	err := json.Unmarshal(data, &_data)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := newEvaluatorWithOptions(r.opts).evaluate(r.logic, _data)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(&result)
+}
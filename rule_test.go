@@ -0,0 +1,63 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestCompile(t *testing.T) {
+	rule, err := jsonlogic.Compile(json.RawMessage(`{">": [{"var": "age"}, 18]}`))
+	assert.NoError(t, err)
+
+	output, err := rule.ApplyRaw(json.RawMessage(`{"age": 20}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `true`, string(output))
+
+	result, err := rule.Apply(map[string]any{"age": float64(10)})
+	assert.NoError(t, err)
+	assert.Equal(t, false, result)
+
+	_, err = rule.Apply(map[string]any{"age": 10})
+	assert.Error(t, err)
+}
+
+func TestCompileInvalidRule(t *testing.T) {
+	_, err := jsonlogic.Compile(json.RawMessage(`{"unknown_operator": [1]}`))
+	assert.ErrorIs(t, err, jsonlogic.ErrInvalidRule)
+
+	_, err = jsonlogic.Compile(json.RawMessage(`{`))
+	assert.Error(t, err)
+
+	_, err = jsonlogic.CompileInterface(map[string]any{"==": []any{1, 1}})
+	assert.Error(t, err)
+}
+
+func TestCompileWithOptions(t *testing.T) {
+	rule, err := jsonlogic.CompileWithOptions(json.RawMessage(`{"cat": ["a ", {"var": "b"}]}`), jsonlogic.Options{Profile: jsonlogic.ProfileJsonLogicJS})
+	assert.NoError(t, err)
+	assert.Equal(t, jsonlogic.Options{Profile: jsonlogic.ProfileJsonLogicJS}, rule.Options())
+
+	output, err := rule.ApplyRaw(json.RawMessage(`{"b": "b "}`))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `"a b "`, string(output))
+
+	strict, err := jsonlogic.CompileInterfaceWithOptions(map[string]any{"var": "missing"}, jsonlogic.Options{Strict: true})
+	assert.NoError(t, err)
+
+	_, err = strict.Apply(map[string]any{})
+	var missing *jsonlogic.MissingVariableError
+	assert.ErrorAs(t, err, &missing)
+
+	engine := jsonlogic.NewEngine(jsonlogic.Options{Strict: true})
+	compiled, err := engine.Compile(json.RawMessage(`{"var": "missing"}`))
+	assert.NoError(t, err)
+	_, err = compiled.ApplyRaw(nil)
+	assert.ErrorAs(t, err, &missing)
+
+	_, err = jsonlogic.CompileWithOptions(json.RawMessage(`{"unknown_operator": [1]}`), jsonlogic.Options{})
+	assert.ErrorIs(t, err, jsonlogic.ErrInvalidRule)
+}
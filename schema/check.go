@@ -0,0 +1,665 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// Severity tells whether a diagnostic is an error, or a warning about a
+// conversion that may be intended.
+type Severity int
+
+const (
+	Error Severity = iota
+	Warning
+)
+
+func (s Severity) String() string {
+	if s == Warning {
+		return "warning"
+	}
+	return "error"
+}
+
+// Diagnostic is a problem of a rule found by Check. Path locates the
+// operation, or its operand, in the rule.
+type Diagnostic struct {
+	Severity Severity
+	Path     jsonlogic.Path
+	Message  string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s", d.Severity, location(d.Path), d.Message)
+}
+
+// maxRefs bounds the $ref followed to resolve a schema.
+const maxRefs = 32
+
+// Check type-checks the rule against a schema of its data, following the
+// conversions of the operators:
+//   - the variables must be properties of the schema, unless their object
+//     allows additional properties or declares none, and read the keys of
+//     objects only
+//   - "==", "!=", "<", "<=", ">" and ">=" report comparing strings with
+//     numbers, which converts the strings to numbers, and comparing arrays or
+//     objects; comparing booleans is a warning
+//   - "===" and "!==" report comparing values of different types, which are
+//     never strictly equal
+//   - the arithmetic operators report arrays and objects, and warn about
+//     strings and booleans converted to numbers
+//   - map, filter, reduce, all, none and some must iterate over arrays, and
+//     their bodies are checked against the schema of the elements
+//   - "in" must look into an array or a string
+//   - the arguments of the operators registered with RegisterFunc1,
+//     RegisterFunc2 or RegisterFunc3 must convert to the types of their
+//     signature
+//
+// Only the operands whose types are known are checked: $ref pointing outside
+// of the schema, paths with wildcards and values computed by other operators
+// are assumed to be right.
+func Check(rule any, data *Schema) []Diagnostic {
+	c := &checker{root: data}
+	c.check(rule, jsonlogic.Path{}, data, nil)
+
+	return c.diagnostics
+}
+
+type checker struct {
+	root        *Schema
+	diagnostics []Diagnostic
+}
+
+// value describes the result of a part of a rule.
+type value struct {
+	// types is nil when any type is possible.
+	types Types
+
+	// schema describes the variables, and name is their path.
+	schema *Schema
+	name   string
+
+	literal   any
+	isLiteral bool
+}
+
+func (v value) describe() string {
+	switch {
+	case v.name != "":
+		return fmt.Sprintf("%q (%s)", v.name, strings.Join(v.types, " or "))
+	case v.isLiteral:
+		encoded, _ := json.Marshal(v.literal)
+		return fmt.Sprintf("%s (%s)", encoded, strings.Join(v.types, " or "))
+	}
+	return "a " + strings.Join(v.types, " or ")
+}
+
+// only reports whether the types of the value are known and all in the set.
+func (v value) only(set ...string) bool {
+	if v.types == nil {
+		return false
+	}
+	for _, typ := range v.types {
+		if !Types(set).Has(typ) {
+			return false
+		}
+	}
+	return true
+}
+
+func (c *checker) report(severity Severity, at jsonlogic.Path, format string, args ...any) {
+	c.diagnostics = append(c.diagnostics, Diagnostic{Severity: severity, Path: at, Message: fmt.Sprintf(format, args...)})
+}
+
+// check checks the rule, located at the path, reading data described by
+// scope, and returns the value it produces. Bound holds the names bound by
+// "let".
+func (c *checker) check(rule any, at jsonlogic.Path, scope *Schema, bound map[string]bool) value {
+	switch v := rule.(type) {
+	case []any:
+		for i, item := range v {
+			c.check(item, child(at, strconv.Itoa(i)), scope, bound)
+		}
+		if isLiteral(v) {
+			return value{types: Types{"array"}, literal: v, isLiteral: true}
+		}
+		return value{types: Types{"array"}}
+	case map[string]any:
+		if len(v) != 1 {
+			return value{types: Types{"object"}, literal: v, isLiteral: true}
+		}
+	default:
+		return value{types: Types{literalType(v)}, literal: v, isLiteral: true}
+	}
+
+	for operator, values := range rule.(map[string]any) {
+		args, isList := values.([]any)
+		if !isList {
+			args = []any{values}
+		}
+		argAt := func(i int) jsonlogic.Path {
+			if !isList {
+				return child(at, operator)
+			}
+			return child(at, operator, strconv.Itoa(i))
+		}
+		checkOperands := func() []value {
+			result := make([]value, len(args))
+			for i, arg := range args {
+				result[i] = c.check(arg, argAt(i), scope, bound)
+			}
+			return result
+		}
+
+		switch operator {
+		case "var", "val":
+			return c.variable(rule, values, at, scope, bound)
+		case "missing":
+			for i, arg := range args {
+				c.paths(arg, argAt(i), scope, bound)
+			}
+			return value{types: Types{"array"}}
+		case "missing_some":
+			operands := checkOperands()
+			if len(args) == 2 && operands[1].isLiteral {
+				c.paths(args[1], argAt(1), scope, bound)
+			}
+			return value{types: Types{"array"}}
+		case "<", "<=", ">", ">=", "==", "!=":
+			operands := checkOperands()
+			for i := 0; i+1 < len(operands); i++ {
+				c.compare(operator, operands[i], operands[i+1], at)
+			}
+			return value{types: Types{"boolean"}}
+		case "===", "!==":
+			operands := checkOperands()
+			if len(operands) == 2 {
+				c.compareStrictly(operator, operands[0], operands[1], at)
+			}
+			return value{types: Types{"boolean"}}
+		case "+", "-", "*", "/", "%", "min", "max", "abs":
+			for i, operand := range checkOperands() {
+				c.number(operator, operand, argAt(i))
+			}
+			return value{types: Types{"number"}}
+		case "!", "!!":
+			checkOperands()
+			return value{types: Types{"boolean"}}
+		case "and", "or":
+			return value{types: unionAll(checkOperands())}
+		case "if", "?:":
+			operands := checkOperands()
+			var results []value
+			for i := 1; i < len(operands); i += 2 {
+				results = append(results, operands[i])
+			}
+			if len(operands)%2 == 1 {
+				results = append(results, operands[len(operands)-1])
+			} else {
+				results = append(results, value{types: Types{"null"}})
+			}
+			return value{types: unionAll(results)}
+		case "in":
+			operands := checkOperands()
+			if len(operands) == 2 && operands[1].types != nil && !operands[1].types.Has("array") && !operands[1].types.Has("string") {
+				c.report(Error, argAt(1), "\"in\" looks into %s, not an array or a string", operands[1].describe())
+			}
+			if len(operands) == 2 {
+				c.enumerated(operands[1], operands[0], argAt(0))
+			}
+			return value{types: Types{"boolean"}}
+		case "cat", "substr":
+			checkOperands()
+			return value{types: Types{"string"}}
+		case "merge":
+			checkOperands()
+			return value{types: Types{"array"}}
+		case "contains_all", "contains_any", "contains_none":
+			for i, operand := range checkOperands() {
+				c.expect(operator, operand, argAt(i), "array")
+			}
+			return value{types: Types{"boolean"}}
+		case "map", "filter", "reduce", "all", "none", "some":
+			if len(args) < 2 {
+				checkOperands()
+				return value{}
+			}
+			return c.iteration(operator, args, argAt, scope, bound)
+		case "let":
+			bindings, ok := args[0].(map[string]any)
+			if len(args) != 2 || !ok {
+				checkOperands()
+				return value{}
+			}
+			inner := make(map[string]bool, len(bound)+len(bindings))
+			for name := range bound {
+				inner[name] = true
+			}
+			for name, binding := range bindings {
+				c.check(binding, child(argAt(0), name), scope, bound)
+				inner[name] = true
+			}
+			return c.check(args[1], argAt(1), scope, inner)
+		}
+
+		operands := checkOperands()
+		signature, ok := jsonlogic.LookupSignature(operator)
+		if !ok {
+			return value{}
+		}
+		for i, operand := range operands {
+			if i < len(signature.Args) && signature.Args[i] != "any" {
+				c.expect(operator, operand, argAt(i), signature.Args[i])
+			}
+		}
+		if signature.Result == "any" {
+			return value{}
+		}
+		return value{types: Types{signature.Result}}
+	}
+
+	return value{}
+}
+
+// variable checks the path of a "var" or "val" against the schema.
+func (c *checker) variable(rule, values any, at jsonlogic.Path, scope *Schema, bound map[string]bool) value {
+	path, def, ok := variable(rule, bound)
+	if !ok || scope == nil {
+		c.check(values, at, scope, bound)
+		return value{}
+	}
+
+	s := c.lookup(scope, path, at)
+	if s == nil {
+		return value{}
+	}
+
+	v := value{types: c.typesOf(s, 0), schema: s, name: path.String()}
+	if v.name == "" {
+		v.name = "(element)"
+	}
+	if def != nil && v.types != nil {
+		v.types = appendType(v.types, literalType(def))
+	}
+
+	return v
+}
+
+// paths checks the paths listed by "missing" and "missing_some".
+func (c *checker) paths(arg any, at jsonlogic.Path, scope *Schema, bound map[string]bool) {
+	if list, ok := arg.([]any); ok {
+		for i, item := range list {
+			c.paths(item, child(at, strconv.Itoa(i)), scope, bound)
+		}
+		return
+	}
+
+	if !isLiteral(arg) {
+		c.check(arg, at, scope, bound)
+		return
+	}
+
+	if p, ok := jsonlogic.ParsePath(arg); ok && scope != nil {
+		c.lookup(scope, p, at)
+	}
+}
+
+// lookup returns the schema of the path, or nil when it's unknown.
+func (c *checker) lookup(s *Schema, path jsonlogic.Path, at jsonlogic.Path) *Schema {
+	for i, key := range path {
+		s = c.resolve(s)
+		if s == nil || key == "*" || key == "**" {
+			return nil
+		}
+
+		types := c.typesOf(s, 0)
+		if isIndex(key) && (types == nil || types.Has("array")) {
+			s = c.itemsOf(s, 0)
+			continue
+		}
+
+		if types != nil && !types.Has("object") {
+			c.report(Error, at, "%q reads %q of %q, which is a %s, not an object", path.String(), key, path[:i].String(), strings.Join(types, " or "))
+			return nil
+		}
+
+		property, found, closed := c.property(s, key, 0)
+		if !found {
+			if closed {
+				c.report(Error, at, "%q is not a field of the data", path[:i+1].String())
+			}
+			return nil
+		}
+		s = property
+	}
+
+	return s
+}
+
+// resolve follows the $ref of the schema, and returns nil when they point
+// outside of the root schema.
+func (c *checker) resolve(s *Schema) *Schema {
+	for i := 0; s != nil && s.Ref != ""; i++ {
+		if i == maxRefs || !strings.HasPrefix(s.Ref, "#") {
+			return nil
+		}
+
+		target := c.root
+		pointer := strings.TrimPrefix(s.Ref, "#")
+		if pointer != "" {
+			unescaper := strings.NewReplacer("~1", "/", "~0", "~")
+			keys := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+			for j := 0; target != nil && j < len(keys); j++ {
+				key := unescaper.Replace(keys[j])
+				switch {
+				case key == "items":
+					target = target.Items
+				case key == "additionalProperties":
+					target = target.AdditionalProperties
+				case j+1 < len(keys) && (key == "$defs" || key == "definitions" || key == "properties"):
+					name := unescaper.Replace(keys[j+1])
+					j++
+					switch key {
+					case "$defs":
+						target = target.Defs[name]
+					case "definitions":
+						target = target.Definitions[name]
+					default:
+						target = target.Properties[name]
+					}
+				default:
+					target = nil
+				}
+			}
+		}
+		s = target
+	}
+
+	return s
+}
+
+// typesOf returns the types allowed by the schema, or nil when it allows any.
+func (c *checker) typesOf(s *Schema, depth int) Types {
+	s = c.resolve(s)
+	if s == nil || depth > maxRefs {
+		return nil
+	}
+
+	if len(s.Type) > 0 {
+		return s.Type
+	}
+
+	if len(s.Enum) > 0 {
+		var types Types
+		for _, v := range s.Enum {
+			types = appendType(types, literalType(v))
+		}
+		return types
+	}
+
+	var types Types
+	for _, sub := range s.AllOf {
+		if t := c.typesOf(sub, depth+1); t != nil {
+			if types == nil {
+				types = t
+			} else {
+				types = intersect(types, t)
+			}
+		}
+	}
+	if types != nil {
+		return types
+	}
+
+	alternatives := append(append([]*Schema(nil), s.AnyOf...), s.OneOf...)
+	for _, sub := range alternatives {
+		t := c.typesOf(sub, depth+1)
+		if t == nil {
+			return nil
+		}
+		types = union(types, t)
+	}
+
+	return types
+}
+
+// property returns the schema of a key of the objects valid against the
+// schema. Found is false when the key isn't declared, and closed tells
+// whether undeclared keys are errors: when additional properties are
+// forbidden, or when the schema declares properties without describing the
+// additional ones.
+func (c *checker) property(s *Schema, key string, depth int) (property *Schema, found, closed bool) {
+	s = c.resolve(s)
+	if s == nil || depth > maxRefs {
+		return nil, false, false
+	}
+
+	if p, ok := s.Properties[key]; ok {
+		return p, true, true
+	}
+
+	if s.AdditionalProperties != nil {
+		if s.AdditionalProperties.never() {
+			return nil, false, true
+		}
+		return s.AdditionalProperties, true, false
+	}
+
+	closed = len(s.Properties) > 0
+	for _, sub := range s.AllOf {
+		p, found, subClosed := c.property(sub, key, depth+1)
+		if found {
+			return p, true, true
+		}
+		closed = closed || subClosed
+	}
+
+	alternatives := append(append([]*Schema(nil), s.AnyOf...), s.OneOf...)
+	allClosed := len(alternatives) > 0
+	for _, sub := range alternatives {
+		p, found, subClosed := c.property(sub, key, depth+1)
+		if found {
+			return p, true, true
+		}
+		allClosed = allClosed && subClosed
+	}
+
+	return nil, false, closed || allClosed
+}
+
+// itemsOf returns the schema of the elements of the arrays valid against the
+// schema, or nil when it's unknown.
+func (c *checker) itemsOf(s *Schema, depth int) *Schema {
+	s = c.resolve(s)
+	if s == nil || depth > maxRefs {
+		return nil
+	}
+
+	if s.Items != nil {
+		return s.Items
+	}
+
+	for _, sub := range append(append(append([]*Schema(nil), s.AllOf...), s.AnyOf...), s.OneOf...) {
+		if items := c.itemsOf(sub, depth+1); items != nil {
+			return items
+		}
+	}
+
+	return nil
+}
+
+// compare checks the operands of "==", "!=", "<", "<=", ">" and ">=", which
+// compare strings as strings and convert the other values to numbers.
+func (c *checker) compare(operator string, a, b value, at jsonlogic.Path) {
+	for _, operand := range []value{a, b} {
+		if operand.only("array", "object") {
+			c.report(Error, at, "%q compares %s, converted to a number", operator, operand.describe())
+			return
+		}
+	}
+
+	switch {
+	case a.only("string") && b.only("number") || a.only("number") && b.only("string"):
+		c.report(Error, at, "%q compares %s with %s: the string is converted to a number", operator, a.describe(), b.describe())
+	case a.only("boolean") && b.only("number", "string") || a.only("number", "string") && b.only("boolean"):
+		c.report(Warning, at, "%q compares %s with %s: the boolean is converted to a number", operator, a.describe(), b.describe())
+	}
+
+	if operator == "==" || operator == "!=" {
+		c.equalsEnum(operator, a, b, at)
+		c.equalsEnum(operator, b, a, at)
+	}
+}
+
+// compareStrictly checks the operands of "===" and "!==", which are never
+// equal when they have different types.
+func (c *checker) compareStrictly(operator string, a, b value, at jsonlogic.Path) {
+	if a.types != nil && b.types != nil && len(intersect(a.types, b.types)) == 0 {
+		result := "false"
+		if operator == "!==" {
+			result = "true"
+		}
+		c.report(Error, at, "%q compares %s with %s, which are never strictly equal: it's always %s", operator, a.describe(), b.describe(), result)
+		return
+	}
+
+	c.equalsEnum(operator, a, b, at)
+	c.equalsEnum(operator, b, a, at)
+}
+
+// equalsEnum warns about comparing a variable restricted to an enum with a
+// literal out of it.
+func (c *checker) equalsEnum(operator string, variable, literal value, at jsonlogic.Path) {
+	if variable.schema == nil || !literal.isLiteral || literal.literal == nil {
+		return
+	}
+
+	s := c.resolve(variable.schema)
+	if s == nil || len(s.Enum) == 0 || enumHas(s.Enum, literal.literal) {
+		return
+	}
+
+	c.report(Warning, at, "%q compares %s with %s, which isn't one of its values", operator, variable.describe(), literal.describe())
+}
+
+// enumerated warns about looking for a variable restricted to an enum in a
+// literal list without any of its values.
+func (c *checker) enumerated(list, variable value, at jsonlogic.Path) {
+	if variable.schema == nil || !list.isLiteral {
+		return
+	}
+
+	items, ok := list.literal.([]any)
+	s := c.resolve(variable.schema)
+	if !ok || s == nil || len(s.Enum) == 0 {
+		return
+	}
+
+	for _, item := range items {
+		if enumHas(s.Enum, item) {
+			return
+		}
+	}
+
+	c.report(Warning, at, "\"in\" looks for %s in a list without any of its values", variable.describe())
+}
+
+func enumHas(enum []any, v any) bool {
+	key, _ := json.Marshal(v)
+	for _, e := range enum {
+		if encoded, _ := json.Marshal(e); string(encoded) == string(key) {
+			return true
+		}
+	}
+	return false
+}
+
+// number checks an operand converted to a number.
+func (c *checker) number(operator string, operand value, at jsonlogic.Path) {
+	switch {
+	case operand.only("array", "object"):
+		c.report(Error, at, "%q uses %s as a number", operator, operand.describe())
+	case operand.only("string"):
+		c.report(Warning, at, "%q converts %s to a number", operator, operand.describe())
+	case operand.only("boolean"):
+		c.report(Warning, at, "%q converts %s to a number", operator, operand.describe())
+	}
+}
+
+// expect checks an operand converted to a type of a signature, with the
+// conversions of the typed operators.
+func (c *checker) expect(operator string, operand value, at jsonlogic.Path, typ string) {
+	if operand.types == nil {
+		return
+	}
+
+	switch typ {
+	case "number", "integer":
+		if operand.only("array", "object") {
+			c.report(Error, at, "%q expects a %s, got %s", operator, typ, operand.describe())
+		} else if operand.only("string") {
+			c.report(Warning, at, "%q converts %s to a %s", operator, operand.describe(), typ)
+		} else if typ == "integer" && operand.only("number") && !operand.only("integer") && !operand.isLiteral {
+			c.report(Warning, at, "%q expects an integer, got %s, which may have a fractional part", operator, operand.describe())
+		}
+	case "string":
+		if operand.only("array", "object") {
+			c.report(Error, at, "%q expects a string, got %s", operator, operand.describe())
+		}
+	case "array", "object":
+		if !operand.types.Has(typ) {
+			c.report(Error, at, "%q expects an %s, got %s", operator, typ, operand.describe())
+		}
+	}
+}
+
+// iteration checks map, filter, reduce, all, none and some, whose bodies read
+// the elements of the array.
+func (c *checker) iteration(operator string, args []any, argAt func(int) jsonlogic.Path, scope *Schema, bound map[string]bool) value {
+	collection := c.check(args[0], argAt(0), scope, bound)
+	if collection.types != nil && !collection.types.Has("array") {
+		c.report(Error, argAt(0), "%q iterates over %s, not an array", operator, collection.describe())
+	}
+
+	var elements *Schema
+	if collection.schema != nil {
+		elements = c.itemsOf(collection.schema, 0)
+	}
+
+	body := elements
+	if operator == "reduce" {
+		if elements == nil {
+			elements = &Schema{}
+		}
+		body = &Schema{Properties: map[string]*Schema{"current": elements, "accumulator": {}}}
+	}
+	c.check(args[1], argAt(1), body, bound)
+
+	for i := 2; i < len(args); i++ {
+		c.check(args[i], argAt(i), scope, bound)
+	}
+
+	switch operator {
+	case "map", "filter":
+		return value{types: Types{"array"}}
+	case "reduce":
+		return value{}
+	}
+	return value{types: Types{"boolean"}}
+}
+
+// unionAll returns the types of all the values, or nil when one allows any.
+func unionAll(values []value) Types {
+	var types Types
+	for _, v := range values {
+		if v.types == nil {
+			return nil
+		}
+		types = union(types, v.types)
+	}
+	return types
+}
@@ -0,0 +1,171 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/schema"
+)
+
+const eventSchema = `{
+	"$schema": "https://json-schema.org/draft/2020-12/schema",
+	"type": "object",
+	"properties": {
+		"age": {"type": "integer"},
+		"name": {"type": "string"},
+		"vip": {"type": "boolean"},
+		"status": {"enum": ["active", "blocked"]},
+		"address": {"$ref": "#/$defs/address"},
+		"items": {"type": "array", "items": {"type": "object", "properties": {"price": {"type": "number"}, "sku": {"type": "string"}}}},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"metadata": {"type": "object", "additionalProperties": {"type": "string"}},
+		"extra": {}
+	},
+	"$defs": {
+		"address": {"type": "object", "properties": {"country": {"type": "string"}}, "additionalProperties": false}
+	}
+}`
+
+func loadSchema(t *testing.T, s string) *schema.Schema {
+	t.Helper()
+
+	var parsed schema.Schema
+	if err := json.Unmarshal([]byte(s), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	return &parsed
+}
+
+func TestCheck(t *testing.T) {
+	data := loadSchema(t, eventSchema)
+
+	scenarios := map[string]struct {
+		rule        string
+		diagnostics []string
+	}{
+		"well typed": {
+			rule: `{"and": [
+				{">=": [{"var": "age"}, 18]},
+				{"==": [{"var": "address.country"}, "BR"]},
+				{"in": ["gold", {"var": "tags"}]},
+				{"some": [{"var": "items"}, {">": [{"var": "price"}, 10]}]},
+				{"==": [{"var": "metadata.source"}, "web"]},
+				{"==": [{"var": "extra.anything.goes"}, 1]},
+				{"missing": ["name", "address.country"]},
+				{"<": [{"reduce": [{"var": "items"}, {"+": [{"var": "current.price"}, {"var": "accumulator"}]}, 0]}, 100]}
+			]}`,
+		},
+		"unknown fields": {
+			rule: `{"and": [{"var": "nmae"}, {"var": "address.city"}, {"missing": ["address.zip"]}, {"some": [{"var": "items"}, {"var": "qty"}]}]}`,
+			diagnostics: []string{
+				`error: /and/0: "nmae" is not a field of the data`,
+				`error: /and/1: "address.city" is not a field of the data`,
+				`error: /and/2/missing/0: "address.zip" is not a field of the data`,
+				`error: /and/3/some/1: "qty" is not a field of the data`,
+			},
+		},
+		"string compared with a number": {
+			rule: `{"or": [{">": [{"var": "name"}, 5]}, {"==": [10, {"var": "name"}]}]}`,
+			diagnostics: []string{
+				`error: /or/0: ">" compares "name" (string) with 5 (number): the string is converted to a number`,
+				`error: /or/1: "==" compares 10 (number) with "name" (string): the string is converted to a number`,
+			},
+		},
+		"strict equality of different types": {
+			rule: `{"and": [{"===": [{"var": "age"}, "18"]}, {"!==": [{"var": "vip"}, 1]}, {"===": [{"var": "age"}, 18]}]}`,
+			diagnostics: []string{
+				`error: /and/0: "===" compares "age" (integer) with "18" (string), which are never strictly equal: it's always false`,
+				`error: /and/1: "!==" compares "vip" (boolean) with 1 (number), which are never strictly equal: it's always true`,
+			},
+		},
+		"booleans and enums": {
+			rule: `{"and": [{"==": [{"var": "vip"}, 1]}, {"==": [{"var": "status"}, "deleted"]}, {"in": [{"var": "status"}, ["gone", "lost"]]}]}`,
+			diagnostics: []string{
+				`warning: /and/0: "==" compares "vip" (boolean) with 1 (number): the boolean is converted to a number`,
+				`warning: /and/1: "==" compares "status" (string) with "deleted" (string), which isn't one of its values`,
+				`warning: /and/2/in/0: "in" looks for "status" (string) in a list without any of its values`,
+			},
+		},
+		"iterations over non-arrays": {
+			rule: `{"or": [{"all": [{"var": "name"}, true]}, {"map": [{"var": "address"}, 1]}]}`,
+			diagnostics: []string{
+				`error: /or/0/all/0: "all" iterates over "name" (string), not an array`,
+				`error: /or/1/map/0: "map" iterates over "address" (object), not an array`,
+			},
+		},
+		"arithmetic": {
+			rule: `{"+": [{"var": "name"}, {"var": "tags"}, {"var": "age"}, {"cat": ["a"]}]}`,
+			diagnostics: []string{
+				`warning: /+/0: "+" converts "name" (string) to a number`,
+				`error: /+/1: "+" uses "tags" (array) as a number`,
+				`warning: /+/3: "+" converts a string to a number`,
+			},
+		},
+		"keys of scalars": {
+			rule: `{"var": "name.first"}`,
+			diagnostics: []string{
+				`error: (root): "name.first" reads "first" of "name", which is a string, not an object`,
+			},
+		},
+		"in": {
+			rule: `{"in": ["a", {"var": "age"}]}`,
+			diagnostics: []string{
+				`error: /in/1: "in" looks into "age" (integer), not an array or a string`,
+			},
+		},
+		"let": {
+			rule: `{"let": [{"limit": {"var": "age"}}, {"<": [{"var": "limit"}, 10]}]}`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			var diagnostics []string
+			for _, d := range schema.Check(parseRule(t, scenario.rule), data) {
+				diagnostics = append(diagnostics, d.String())
+			}
+			assert.Equal(t, scenario.diagnostics, diagnostics)
+		})
+	}
+}
+
+func TestCheckWithSignatures(t *testing.T) {
+	jsonlogic.RegisterFunc2("schema_round_to", func(n float64, digits int) (float64, error) { return n, nil })
+
+	data := loadSchema(t, eventSchema)
+	diagnostics := schema.Check(parseRule(t, `{"schema_round_to": [{"var": "tags"}, {"var": "items.0.price"}]}`), data)
+
+	if assert.Len(t, diagnostics, 2) {
+		assert.Equal(t, schema.Error, diagnostics[0].Severity)
+		assert.Equal(t, jsonlogic.Path{"schema_round_to", "0"}, diagnostics[0].Path)
+		assert.Equal(t, `"schema_round_to" expects a number, got "tags" (array)`, diagnostics[0].Message)
+		assert.Equal(t, `warning: /schema_round_to/1: "schema_round_to" expects an integer, got "items.0.price" (number), which may have a fractional part`, diagnostics[1].String())
+	}
+}
+
+func TestCheckSchemaCombinations(t *testing.T) {
+	data := loadSchema(t, `{
+		"definitions": {"id": {"type": "string"}},
+		"allOf": [
+			{"properties": {"id": {"$ref": "#/definitions/id"}}},
+			{"properties": {"kind": {"anyOf": [{"type": "string"}, {"type": "null"}]}}}
+		]
+	}`)
+
+	diagnostics := schema.Check(parseRule(t, `{"and": [{"<": [{"var": "id"}, 1]}, {"==": [{"var": "kind"}, "a"]}, {"var": "other"}]}`), data)
+
+	var messages []string
+	for _, d := range diagnostics {
+		messages = append(messages, d.String())
+	}
+	assert.Equal(t, []string{
+		`error: /and/0: "<" compares "id" (string) with 1 (number): the string is converted to a number`,
+		`error: /and/2: "other" is not a field of the data`,
+	}, messages)
+
+	assert.Empty(t, schema.Check(parseRule(t, `{"var": "anything"}`), loadSchema(t, `true`)))
+	assert.Len(t, schema.Check(parseRule(t, `{"var": "anything"}`), loadSchema(t, `{"additionalProperties": false}`)), 1)
+}
@@ -0,0 +1,513 @@
+package schema
+
+import (
+	"encoding/json"
+	"strconv"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// InferSchema returns a schema of the data read by the rule, from the way it
+// uses each variable:
+//   - the operands of arithmetic operators are numbers, and so are the ones
+//     of "<", "<=", ">" and ">=" unless they're compared to a string
+//   - the operands of "==", "!=", "===" and "!==" compared to a literal have
+//     its type
+//   - the first operand of "in" compared to a list of literals is one of
+//     them, and the second operand is an array or a string
+//   - the first operand of "substr" is a string, and the other ones numbers
+//   - the variables listed by "missing" are required
+//   - the first operand of map, filter, reduce, all, none and some is an
+//     array, whose elements are described by the body
+//   - the arguments of the operators registered with RegisterFunc1,
+//     RegisterFunc2 or RegisterFunc3 have the types of their signature
+//
+// Only the variables with a literal path are described, up to the first "*"
+// or "**" segment, and numeric segments are taken as array indexes. The
+// variables used as values of incompatible types allow every type they're
+// used as, with a $comment listing the uses, and are returned as conflicts.
+func InferSchema(rule any) (*Schema, []Conflict) {
+	root := &field{}
+	infer(rule, jsonlogic.Path{}, root, nil)
+
+	var conflicts []Conflict
+	s := root.schema(jsonlogic.Path{}, &conflicts)
+	s.Schema = Draft
+	if s.Type == nil {
+		s.Type = Types{"object"}
+	}
+
+	return s, conflicts
+}
+
+// field gathers the uses of a value of the data.
+type field struct {
+	uses       []Use
+	enum       []any
+	def        any
+	required   bool
+	keys       []string
+	properties map[string]*field
+	items      *field
+}
+
+// property returns the field of a key of the object, used at the location.
+func (f *field) property(key string, at jsonlogic.Path) *field {
+	f.use(Types{"object"}, at)
+
+	if f.properties == nil {
+		f.properties = make(map[string]*field)
+	}
+
+	p, ok := f.properties[key]
+	if !ok {
+		p = &field{}
+		f.properties[key] = p
+		f.keys = append(f.keys, key)
+	}
+
+	return p
+}
+
+// elements returns the field of the elements of the array, used at the location.
+func (f *field) elements(at jsonlogic.Path) *field {
+	f.use(Types{"array"}, at)
+
+	if f.items == nil {
+		f.items = &field{}
+	}
+
+	return f.items
+}
+
+func (f *field) use(types Types, at jsonlogic.Path) {
+	if len(types) > 0 {
+		f.uses = append(f.uses, Use{Types: types, Rule: at})
+	}
+}
+
+// lookup returns the field of the path, or nil when it goes through a
+// wildcard.
+func (f *field) lookup(path jsonlogic.Path, at jsonlogic.Path) *field {
+	for _, key := range path {
+		switch {
+		case key == "*" || key == "**":
+			return nil
+		case isIndex(key):
+			f = f.elements(at)
+		default:
+			f = f.property(key, at)
+		}
+	}
+
+	return f
+}
+
+func isIndex(key string) bool {
+	_, err := strconv.Atoi(key)
+	return err == nil
+}
+
+func (f *field) schema(path jsonlogic.Path, conflicts *[]Conflict) *Schema {
+	s := &Schema{Default: f.def}
+
+	if len(f.uses) > 0 {
+		types, ok := f.uses[0].Types, true
+		for _, use := range f.uses[1:] {
+			types = intersect(types, use.Types)
+		}
+		if len(types) == 0 {
+			ok = false
+			for _, use := range f.uses {
+				types = union(types, use.Types)
+			}
+		}
+		s.Type = types
+
+		if !ok {
+			conflict := Conflict{Path: path, Uses: f.uses}
+			*conflicts = append(*conflicts, conflict)
+			s.Comment = "conflict: " + conflict.String()
+		}
+	}
+
+	s.Enum = f.enum
+
+	for _, key := range f.keys {
+		if s.Properties == nil {
+			s.Properties = make(map[string]*Schema)
+		}
+		p := f.properties[key]
+		s.Properties[key] = p.schema(child(path, key), conflicts)
+		if p.required {
+			s.Required = append(s.Required, key)
+		}
+	}
+
+	if f.items != nil {
+		s.Items = f.items.schema(child(path, "*"), conflicts)
+	}
+
+	return s
+}
+
+// intersect returns the types in both sets, counting integers as numbers.
+func intersect(a, b Types) Types {
+	var result Types
+	for _, typ := range a {
+		switch {
+		case b.Has(typ):
+			result = appendType(result, typ)
+		case typ == "number" && b.Has("integer"):
+			result = appendType(result, "integer")
+		}
+	}
+	return result
+}
+
+func union(a, b Types) Types {
+	for _, typ := range b {
+		a = appendType(a, typ)
+	}
+	return a
+}
+
+func appendType(types Types, typ string) Types {
+	for _, t := range types {
+		if t == typ {
+			return types
+		}
+	}
+	return append(types, typ)
+}
+
+// numericOperators are the operators converting their operands to numbers.
+var numericOperators = map[string]bool{
+	"+": true, "-": true, "*": true, "/": true, "%": true,
+	"min": true, "max": true, "abs": true,
+}
+
+// iterators evaluate their second argument once per element of their first.
+var iterators = map[string]bool{
+	"map": true, "filter": true, "reduce": true, "all": true, "none": true, "some": true,
+}
+
+// infer records the uses of the variables of the rule, located at the path,
+// reading the data described by scope. Bound holds the names bound by "let".
+func infer(rule any, at jsonlogic.Path, scope *field, bound map[string]bool) {
+	switch value := rule.(type) {
+	case []any:
+		for i, item := range value {
+			infer(item, child(at, strconv.Itoa(i)), scope, bound)
+		}
+		return
+	case map[string]any:
+		if len(value) != 1 {
+			return
+		}
+	default:
+		return
+	}
+
+	for operator, values := range rule.(map[string]any) {
+		args, isList := values.([]any)
+		if !isList {
+			args = []any{values}
+		}
+		argAt := func(i int) jsonlogic.Path {
+			if !isList {
+				return child(at, operator)
+			}
+			return child(at, operator, strconv.Itoa(i))
+		}
+		use := func(i int, types Types, enum []any) *field {
+			return useArgument(args[i], argAt(i), scope, bound, types, enum)
+		}
+		rest := func(from int) {
+			for i := from; i < len(args); i++ {
+				use(i, nil, nil)
+			}
+		}
+
+		switch {
+		case operator == "var" || operator == "val":
+			if _, _, ok := variable(rule, bound); ok {
+				useArgument(rule, at, scope, bound, nil, nil)
+			} else {
+				rest(0)
+			}
+		case operator == "<" || operator == "<=" || operator == ">" || operator == ">=":
+			types := Types{"number"}
+			for _, arg := range args {
+				if _, ok := arg.(string); ok {
+					types = Types{"string"}
+				}
+			}
+			for i := range args {
+				use(i, types, nil)
+			}
+		case operator == "==" || operator == "!=" || operator == "===" || operator == "!==":
+			for i := range args {
+				var types Types
+				if len(args) == 2 {
+					if typ := literalType(args[1-i]); typ != "" && typ != "null" {
+						types = Types{typ}
+					}
+				}
+				use(i, types, nil)
+			}
+		case operator == "in" && len(args) == 2:
+			needle, haystack := Types(nil), Types(nil)
+			var enum []any
+			switch h := args[1].(type) {
+			case string:
+				needle = Types{"string"}
+			case []any:
+				if isLiteral(h) {
+					for _, item := range h {
+						needle = appendType(needle, literalType(item))
+						enum = appendUnique(enum, item)
+					}
+				}
+			}
+			if typ := literalType(args[0]); typ == "string" {
+				haystack = Types{"array", "string"}
+			} else if typ != "" {
+				haystack = Types{"array"}
+			}
+
+			use(0, needle, enum)
+			if f := use(1, haystack, nil); f != nil && haystack != nil {
+				if f.items == nil {
+					f.items = &field{}
+				}
+				f.items.use(Types{literalType(args[0])}, argAt(1))
+			}
+		case operator == "substr":
+			for i := range args {
+				types := Types{"number"}
+				if i == 0 {
+					types = Types{"string"}
+				}
+				use(i, types, nil)
+			}
+		case numericOperators[operator]:
+			for i := range args {
+				use(i, Types{"number"}, nil)
+			}
+		case operator == "contains_all" || operator == "contains_any" || operator == "contains_none":
+			for i := range args {
+				use(i, Types{"array"}, nil)
+			}
+		case operator == "missing":
+			for i, arg := range args {
+				markRequired(arg, argAt(i), scope, bound)
+			}
+		case operator == "missing_some" && len(args) == 2:
+			use(0, nil, nil)
+			if list, ok := args[1].([]any); ok && isLiteral(list) {
+				for i, arg := range list {
+					if p, ok := jsonlogic.ParsePath(arg); ok {
+						scope.lookup(p, child(argAt(1), strconv.Itoa(i)))
+					}
+				}
+			} else {
+				use(1, nil, nil)
+			}
+		case iterators[operator] && len(args) >= 2:
+			elements := &field{}
+			if f := use(0, Types{"array"}, nil); f != nil {
+				elements = f.elements(argAt(0))
+			}
+
+			body := elements
+			if operator == "reduce" {
+				body = &field{
+					keys:       []string{"current", "accumulator"},
+					properties: map[string]*field{"current": elements, "accumulator": {}},
+				}
+			}
+			infer(args[1], argAt(1), body, bound)
+			rest(2)
+		case operator == "let" && len(args) == 2:
+			bindings, ok := args[0].(map[string]any)
+			if !ok {
+				rest(0)
+				break
+			}
+			inner := make(map[string]bool, len(bound)+len(bindings))
+			for name := range bound {
+				inner[name] = true
+			}
+			for name, binding := range bindings {
+				infer(binding, child(argAt(0), name), scope, bound)
+				inner[name] = true
+			}
+			infer(args[1], argAt(1), scope, inner)
+		default:
+			signature, ok := jsonlogic.LookupSignature(operator)
+			for i := range args {
+				var types Types
+				if ok && i < len(signature.Args) && signature.Args[i] != "any" {
+					types = Types{signature.Args[i]}
+				}
+				use(i, types, nil)
+			}
+		}
+	}
+}
+
+// useArgument records the use of the argument when it's a variable, and
+// returns its field. Other arguments are walked for variables.
+func useArgument(arg any, at jsonlogic.Path, scope *field, bound map[string]bool, types Types, enum []any) *field {
+	path, def, ok := variable(arg, bound)
+	if !ok {
+		infer(arg, at, scope, bound)
+		return nil
+	}
+
+	f := scope.lookup(path, at)
+	if f == nil {
+		return nil
+	}
+
+	f.use(types, at)
+	for _, value := range enum {
+		f.enum = appendUnique(f.enum, value)
+	}
+	if def != nil && f.def == nil {
+		f.def = def
+	}
+
+	return f
+}
+
+// variable returns the path and the default value of a "var" or "val" with a
+// literal path, not reading a name bound by "let".
+func variable(rule any, bound map[string]bool) (jsonlogic.Path, any, bool) {
+	m, ok := rule.(map[string]any)
+	if !ok || len(m) != 1 {
+		return nil, nil, false
+	}
+
+	var path, def any
+	if values, ok := m["var"]; ok {
+		path = values
+		if args, ok := values.([]any); ok {
+			if len(args) == 0 {
+				return nil, nil, false
+			}
+			path = args[0]
+			if len(args) > 1 {
+				def = args[1]
+			}
+		}
+	} else if values, ok := m["val"]; ok {
+		segments, ok := values.([]any)
+		if !ok {
+			segments = []any{values}
+		}
+		if len(segments) > 0 {
+			if _, scoped := segments[0].([]any); scoped {
+				return nil, nil, false
+			}
+		}
+		path = segments
+	} else {
+		return nil, nil, false
+	}
+
+	if !isLiteral(path) || !isLiteral(def) {
+		return nil, nil, false
+	}
+
+	p, ok := jsonlogic.ParsePath(path)
+	if !ok || len(p) > 0 && bound[p[0]] {
+		return nil, nil, false
+	}
+
+	return p, def, true
+}
+
+// markRequired marks the variables listed by "missing" as required, along
+// with the objects holding them.
+func markRequired(arg any, at jsonlogic.Path, scope *field, bound map[string]bool) {
+	if list, ok := arg.([]any); ok {
+		for i, item := range list {
+			markRequired(item, child(at, strconv.Itoa(i)), scope, bound)
+		}
+		return
+	}
+
+	if !isLiteral(arg) {
+		infer(arg, at, scope, bound)
+		return
+	}
+
+	p, ok := jsonlogic.ParsePath(arg)
+	if !ok {
+		return
+	}
+
+	f := scope
+	for _, key := range p {
+		if key == "*" || key == "**" || isIndex(key) {
+			return
+		}
+		f = f.property(key, at)
+		f.required = true
+	}
+}
+
+// literalType returns the JSON type of a literal, or "" for operations.
+func literalType(value any) string {
+	if !isLiteral(value) {
+		return ""
+	}
+
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64, int:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	}
+
+	return "object"
+}
+
+// isLiteral reports whether the value holds no operation.
+func isLiteral(value any) bool {
+	switch v := value.(type) {
+	case map[string]any:
+		return len(v) != 1
+	case []any:
+		for _, item := range v {
+			if !isLiteral(item) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func appendUnique(values []any, value any) []any {
+	key, _ := json.Marshal(value)
+	for _, v := range values {
+		if existing, _ := json.Marshal(v); string(existing) == string(key) {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// child returns a copy of the path extended with the keys.
+func child(path jsonlogic.Path, keys ...string) jsonlogic.Path {
+	return append(path[:len(path):len(path)], keys...)
+}
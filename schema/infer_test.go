@@ -0,0 +1,119 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+	"github.com/diegoholiveira/jsonlogic/v3/schema"
+)
+
+func parseRule(t *testing.T, rule string) any {
+	t.Helper()
+
+	var parsed any
+	if err := json.Unmarshal([]byte(rule), &parsed); err != nil {
+		t.Fatal(err)
+	}
+	return parsed
+}
+
+func TestInferSchema(t *testing.T) {
+	scenarios := map[string]struct {
+		rule   string
+		schema string
+	}{
+		"no variables": {
+			rule:   `{"==": [1, 1]}`,
+			schema: `{"type": "object"}`,
+		},
+		"numeric comparisons": {
+			rule:   `{"and": [{">=": [{"var": "age"}, 18]}, {"<": [0, {"var": "score"}, 10]}]}`,
+			schema: `{"type": "object", "properties": {"age": {"type": "number"}, "score": {"type": "number"}}}`,
+		},
+		"string comparisons": {
+			rule:   `{"<": [{"var": "date"}, "2024-01-01"]}`,
+			schema: `{"type": "object", "properties": {"date": {"type": "string"}}}`,
+		},
+		"equality with literals": {
+			rule:   `{"or": [{"===": [{"var": "active"}, true]}, {"==": [{"var": "deleted"}, null]}]}`,
+			schema: `{"type": "object", "properties": {"active": {"type": "boolean"}, "deleted": {}}}`,
+		},
+		"in a list of strings": {
+			rule:   `{"in": [{"var": "user.country"}, ["BR", "PT"]]}`,
+			schema: `{"type": "object", "properties": {"user": {"type": "object", "properties": {"country": {"type": "string", "enum": ["BR", "PT"]}}}}}`,
+		},
+		"in a variable": {
+			rule:   `{"in": ["vip", {"var": "tags"}]}`,
+			schema: `{"type": "object", "properties": {"tags": {"type": ["array", "string"], "items": {"type": "string"}}}}`,
+		},
+		"missing": {
+			rule:   `{"if": [{"missing": ["name", "address.city"]}, "incomplete", {"cat": ["Hi ", {"var": "name"}]}]}`,
+			schema: `{"type": "object", "properties": {"name": {}, "address": {"type": "object", "properties": {"city": {}}, "required": ["city"]}}, "required": ["name", "address"]}`,
+		},
+		"iterations": {
+			rule:   `{"and": [{"some": [{"var": "items"}, {">": [{"var": "price"}, 10]}]}, {"all": [{"var": "ids"}, {"<": [{"var": ""}, 100]}]}]}`,
+			schema: `{"type": "object", "properties": {"items": {"type": "array", "items": {"type": "object", "properties": {"price": {"type": "number"}}}}, "ids": {"type": "array", "items": {"type": "number"}}}}`,
+		},
+		"reduce": {
+			rule:   `{"reduce": [{"var": "scores"}, {"+": [{"var": "current"}, {"var": "accumulator"}]}, 0]}`,
+			schema: `{"type": "object", "properties": {"scores": {"type": "array", "items": {"type": "number"}}}}`,
+		},
+		"defaults and indexes": {
+			rule:   `{"+": [{"var": ["bonus", 0]}, {"var": "values.0"}]}`,
+			schema: `{"type": "object", "properties": {"bonus": {"type": "number", "default": 0}, "values": {"type": "array", "items": {"type": "number"}}}}`,
+		},
+		"wildcards and let": {
+			rule:   `{"let": [{"limit": {"var": "max"}}, {"<": [{"var": "orders.*.total"}, {"var": "limit"}]}]}`,
+			schema: `{"type": "object", "properties": {"max": {}, "orders": {}}}`,
+		},
+		"substr and arrays": {
+			rule:   `{"and": [{"substr": [{"var": "code"}, {"var": "start"}]}, {"contains_any": [{"var": "roles"}, ["admin"]]}]}`,
+			schema: `{"type": "object", "properties": {"code": {"type": "string"}, "start": {"type": "number"}, "roles": {"type": "array"}}}`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			s, conflicts := schema.InferSchema(parseRule(t, scenario.rule))
+			assert.Empty(t, conflicts)
+			assert.Equal(t, schema.Draft, s.Schema)
+
+			s.Schema = ""
+			encoded, err := json.Marshal(s)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.schema, string(encoded))
+		})
+	}
+}
+
+func TestInferSchemaConflicts(t *testing.T) {
+	rule := parseRule(t, `{"and": [
+		{">=": [{"var": "age"}, 18]},
+		{"==": [{"var": "age"}, "adult"]},
+		{"+": [{"var": "user.id"}]},
+		{"==": [{"var": "user"}, "anonymous"]}
+	]}`)
+
+	s, conflicts := schema.InferSchema(rule)
+
+	if assert.Len(t, conflicts, 2) {
+		assert.Equal(t, jsonlogic.Path{"age"}, conflicts[0].Path)
+		assert.Equal(t, "age: used as number at /and/0/>=/0, string at /and/1/==/0", conflicts[0].String())
+		assert.Equal(t, "user: used as object at /and/2/+/0, string at /and/3/==/0", conflicts[1].String())
+	}
+
+	assert.Equal(t, schema.Types{"number", "string"}, s.Properties["age"].Type)
+	assert.Equal(t, "conflict: age: used as number at /and/0/>=/0, string at /and/1/==/0", s.Properties["age"].Comment)
+}
+
+func TestInferSchemaWithSignatures(t *testing.T) {
+	jsonlogic.RegisterFunc2("schema_repeat", func(s string, n int) (string, error) { return s, nil })
+
+	s, conflicts := schema.InferSchema(parseRule(t, `{"schema_repeat": [{"var": "text"}, {"var": "times"}]}`))
+	assert.Empty(t, conflicts)
+	assert.Equal(t, schema.Types{"string"}, s.Properties["text"].Type)
+	assert.Equal(t, schema.Types{"integer"}, s.Properties["times"].Type)
+}
@@ -0,0 +1,138 @@
+// Package schema relates rules to JSON Schemas (draft 2020-12) describing
+// their data. InferSchema describes the data a rule reads, from the way it
+// uses each variable, and Check type-checks a rule against the schema of the
+// data it's applied to.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+// Draft is the URI of the JSON Schema dialect used.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// Schema is the subset of JSON Schema describing the data of rules. The
+// other keywords are ignored when decoding a schema. The boolean schemas true
+// and false decode to an empty schema and to {"not": {}}.
+type Schema struct {
+	Schema  string             `json:"$schema,omitempty"`
+	Comment string             `json:"$comment,omitempty"`
+	Ref     string             `json:"$ref,omitempty"`
+	Defs    map[string]*Schema `json:"$defs,omitempty"`
+
+	// Definitions holds the definitions of the drafts before 2019-09.
+	Definitions map[string]*Schema `json:"definitions,omitempty"`
+
+	Type    Types `json:"type,omitempty"`
+	Enum    []any `json:"enum,omitempty"`
+	Default any   `json:"default,omitempty"`
+
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+
+	AllOf []*Schema `json:"allOf,omitempty"`
+	AnyOf []*Schema `json:"anyOf,omitempty"`
+	OneOf []*Schema `json:"oneOf,omitempty"`
+	Not   *Schema   `json:"not,omitempty"`
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	var allowed bool
+	if err := json.Unmarshal(data, &allowed); err == nil {
+		*s = Schema{}
+		if !allowed {
+			s.Not = &Schema{}
+		}
+		return nil
+	}
+
+	type plain Schema
+	return json.Unmarshal(data, (*plain)(s))
+}
+
+// never reports whether no value is valid against the schema, like false.
+func (s *Schema) never() bool {
+	return s != nil && s.Not != nil && reflect.DeepEqual(*s.Not, Schema{})
+}
+
+// Types are the JSON types allowed by a schema, named as in JSON Schema:
+// "null", "boolean", "number", "integer", "string", "array" and "object". A
+// single type encodes to a string, and several to an array.
+type Types []string
+
+// Has reports whether the type is allowed, counting integers as numbers.
+func (t Types) Has(typ string) bool {
+	for _, allowed := range t {
+		if allowed == typ || allowed == "number" && typ == "integer" {
+			return true
+		}
+	}
+	return false
+}
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = Types{single}
+		return nil
+	}
+
+	var several []string
+	if err := json.Unmarshal(data, &several); err != nil {
+		return fmt.Errorf("schema: type must be a string or an array of strings")
+	}
+	*t = several
+
+	return nil
+}
+
+// Use is a use of a variable by a rule, requiring one of the Types. Rule
+// locates the operation using the variable.
+type Use struct {
+	Types Types
+	Rule  jsonlogic.Path
+}
+
+// Conflict is a variable used as values of types no value can have at once.
+// Path is the path of the variable in the data, with a "*" segment for the
+// elements of arrays.
+type Conflict struct {
+	Path jsonlogic.Path
+	Uses []Use
+}
+
+func (c Conflict) String() string {
+	uses := make([]string, len(c.Uses))
+	for i, use := range c.Uses {
+		uses[i] = fmt.Sprintf("%s at %s", strings.Join(use.Types, " or "), location(use.Rule))
+	}
+
+	name := c.Path.String()
+	if name == "" {
+		name = "(root)"
+	}
+
+	return fmt.Sprintf("%s: used as %s", name, strings.Join(uses, ", "))
+}
+
+// location returns the path as a JSON Pointer, or "(root)" when it's empty.
+func location(p jsonlogic.Path) string {
+	if len(p) == 0 {
+		return "(root)"
+	}
+	return p.Pointer()
+}
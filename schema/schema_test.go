@@ -0,0 +1,29 @@
+package schema_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/diegoholiveira/jsonlogic/v3/schema"
+)
+
+func TestTypes(t *testing.T) {
+	encoded, err := json.Marshal(schema.Types{"number"})
+	assert.NoError(t, err)
+	assert.Equal(t, `"number"`, string(encoded))
+
+	encoded, err = json.Marshal(schema.Types{"number", "null"})
+	assert.NoError(t, err)
+	assert.Equal(t, `["number","null"]`, string(encoded))
+
+	var s schema.Schema
+	assert.NoError(t, json.Unmarshal([]byte(`{"type": "string", "items": {"type": ["integer", "null"]}}`), &s))
+	assert.Equal(t, schema.Types{"string"}, s.Type)
+	assert.Equal(t, schema.Types{"integer", "null"}, s.Items.Type)
+	assert.Error(t, json.Unmarshal([]byte(`{"type": 1}`), &s))
+
+	assert.True(t, schema.Types{"number"}.Has("integer"))
+	assert.False(t, schema.Types{"integer"}.Has("number"))
+}
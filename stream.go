@@ -0,0 +1,187 @@
+package jsonlogic
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/diegoholiveira/jsonlogic/v3/internal/javascript"
+)
+
+// StreamOptions configures ApplyStream.
+type StreamOptions struct {
+	// Filter writes the records for which the rule is truthy, instead of the
+	// result of the rule for every record.
+	Filter bool
+
+	// Workers is the number of records evaluated in parallel. The output keeps
+	// the order of the input. Values lower than 2 evaluate one record at a time.
+	Workers int
+
+	// OnError is called when the rule fails on a record, with the position of
+	// the record in the input starting at 0. Returning nil skips the record,
+	// returning an error stops the stream with it. When OnError is nil the
+	// stream stops on the first failure.
+	OnError func(index int, err error) error
+
+	// Options overrides the options the rule was compiled with, when set.
+	Options *Options
+}
+
+type streamRecord struct {
+	index  int
+	raw    json.RawMessage
+	output any
+	err    error
+	fatal  bool
+	done   chan struct{}
+}
+
+// ApplyStream applies the rule to each JSON value read from input, which can
+// hold newline-delimited or simply concatenated values, and writes one result
+// per line to output. Records are read as they are evaluated, so memory use
+// doesn't depend on the size of the input. When the stream stops early, it
+// returns once the reads in progress complete, and reads nothing more.
+//
+// Parameters:
+//   - rule: the compiled rule applied to every record
+//   - input: io.Reader with the records
+//   - output: io.Writer receiving the newline-delimited results
+//   - opts: filtering, parallelism and error handling settings
+//
+// Returns:
+//   - err: error if the input is not valid JSON, the output can't be written or a record fails
+func ApplyStream(rule *Rule, input io.Reader, output io.Writer, opts StreamOptions) error {
+	decoder := json.NewDecoder(input)
+	encoder := json.NewEncoder(output)
+
+	evalOpts := rule.opts
+	if opts.Options != nil {
+		evalOpts = *opts.Options
+	}
+
+	write := func(record *streamRecord) error {
+		if record.fatal {
+			return fmt.Errorf("jsonlogic: record %d: %w", record.index, record.err)
+		}
+
+		if record.err != nil {
+			if opts.OnError == nil {
+				return fmt.Errorf("jsonlogic: record %d: %w", record.index, record.err)
+			}
+			return opts.OnError(record.index, record.err)
+		}
+
+		if !opts.Filter {
+			return encoder.Encode(record.output)
+		}
+
+		if javascript.IsTrue(record.output) {
+			return encoder.Encode(record.raw)
+		}
+
+		return nil
+	}
+
+	if opts.Workers < 2 {
+		for index := 0; ; index++ {
+			record := &streamRecord{index: index}
+
+			err := decoder.Decode(&record.raw)
+			if err == io.EOF {
+				return nil
+			}
+
+			if err != nil {
+				record.err = err
+				record.fatal = true
+			} else {
+				record.evaluate(rule, evalOpts)
+			}
+
+			if err := write(record); err != nil {
+				return err
+			}
+		}
+	}
+
+	jobs := make(chan *streamRecord, opts.Workers)
+	pending := make(chan *streamRecord, opts.Workers*2)
+	stop := make(chan struct{})
+
+	// the producer and the workers are done before returning, so the input
+	// isn't read once the stream stopped
+	var running sync.WaitGroup
+	defer running.Wait()
+	defer close(stop)
+
+	running.Add(1 + opts.Workers)
+	go func() {
+		defer running.Done()
+		defer close(pending)
+		defer close(jobs)
+
+		for index := 0; ; index++ {
+			record := &streamRecord{index: index, done: make(chan struct{})}
+
+			err := decoder.Decode(&record.raw)
+			if err == io.EOF {
+				return
+			}
+
+			if err != nil {
+				record.err = err
+				record.fatal = true
+				close(record.done)
+			}
+
+			select {
+			case pending <- record:
+			case <-stop:
+				return
+			}
+
+			if record.fatal {
+				return
+			}
+
+			select {
+			case jobs <- record:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	for i := 0; i < opts.Workers; i++ {
+		go func() {
+			defer running.Done()
+			for record := range jobs {
+				record.evaluate(rule, evalOpts)
+				close(record.done)
+			}
+		}()
+	}
+
+	for record := range pending {
+		<-record.done
+
+		if err := write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *streamRecord) evaluate(rule *Rule, opts Options) {
+	var data any
+
+	r.err = json.Unmarshal(r.raw, &data)
+	if r.err != nil {
+		return
+	}
+
+	r.output, r.err = newEvaluatorWithOptions(opts).evaluate(rule.logic, data)
+}
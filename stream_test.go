@@ -0,0 +1,153 @@
+package jsonlogic_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestApplyStream(t *testing.T) {
+	rule, err := jsonlogic.Compile([]byte(`{"*": [{"var": "n"}, 2]}`))
+	assert.NoError(t, err)
+
+	input := strings.NewReader("{\"n\": 1}\n{\"n\": 2}\n{\n  \"n\": 3\n} {\"n\": 4}")
+
+	var output bytes.Buffer
+	err = jsonlogic.ApplyStream(rule, input, &output, jsonlogic.StreamOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "2\n4\n6\n8\n", output.String())
+}
+
+func TestApplyStreamFilter(t *testing.T) {
+	rule, err := jsonlogic.Compile([]byte(`{">": [{"var": "age"}, 18]}`))
+	assert.NoError(t, err)
+
+	input := strings.NewReader("{\"name\": \"Ana\", \"age\": 30}\n{\"name\": \"Bia\", \"age\": 12}\n{\n  \"name\": \"Caio\",\n  \"age\": 19\n}\n")
+
+	var output bytes.Buffer
+	err = jsonlogic.ApplyStream(rule, input, &output, jsonlogic.StreamOptions{Filter: true})
+	assert.NoError(t, err)
+	assert.Equal(t, "{\"name\":\"Ana\",\"age\":30}\n{\"name\":\"Caio\",\"age\":19}\n", output.String())
+}
+
+func TestApplyStreamWithWorkersKeepsOrder(t *testing.T) {
+	rule, err := jsonlogic.Compile([]byte(`{"+": [{"var": ""}, 1]}`))
+	assert.NoError(t, err)
+
+	var input, expected strings.Builder
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&input, "%d\n", i)
+		fmt.Fprintf(&expected, "%d\n", i+1)
+	}
+
+	var output bytes.Buffer
+	err = jsonlogic.ApplyStream(rule, strings.NewReader(input.String()), &output, jsonlogic.StreamOptions{Workers: 8})
+	assert.NoError(t, err)
+	assert.Equal(t, expected.String(), output.String())
+}
+
+func TestApplyStreamErrors(t *testing.T) {
+	rule, err := jsonlogic.Compile([]byte(`{"reduce": [[1], {"var": "current"}, {"var": "initial"}]}`))
+	assert.NoError(t, err)
+
+	input := "{\"initial\": 1}\n{\"initial\": null}\n{\"initial\": 2}\n"
+
+	for _, workers := range []int{0, 4} {
+		t.Run(fmt.Sprintf("workers:%d", workers), func(t *testing.T) {
+			var output bytes.Buffer
+			err := jsonlogic.ApplyStream(rule, strings.NewReader(input), &output, jsonlogic.StreamOptions{Workers: workers})
+			assert.ErrorContains(t, err, "record 1")
+			assert.Equal(t, "1\n", output.String())
+
+			var skipped []int
+			output.Reset()
+			err = jsonlogic.ApplyStream(rule, strings.NewReader(input), &output, jsonlogic.StreamOptions{
+				Workers: workers,
+				OnError: func(index int, err error) error {
+					skipped = append(skipped, index)
+					return nil
+				},
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, []int{1}, skipped)
+			assert.Equal(t, "1\n1\n", output.String())
+
+			abort := errors.New("abort")
+			err = jsonlogic.ApplyStream(rule, strings.NewReader(input), &output, jsonlogic.StreamOptions{
+				Workers: workers,
+				OnError: func(index int, err error) error { return abort },
+			})
+			assert.ErrorIs(t, err, abort)
+
+			output.Reset()
+			err = jsonlogic.ApplyStream(rule, strings.NewReader("{\"initial\": 1}\n{invalid"), &output, jsonlogic.StreamOptions{Workers: workers})
+			assert.ErrorContains(t, err, "record 1")
+			assert.Equal(t, "1\n", output.String())
+		})
+	}
+}
+
+func TestApplyStreamOptions(t *testing.T) {
+	rule, err := jsonlogic.CompileWithOptions([]byte(`{"var": "n"}`), jsonlogic.Options{Strict: true})
+	assert.NoError(t, err)
+
+	input := "{\"n\": 1}\n{}\n"
+
+	var output bytes.Buffer
+	err = jsonlogic.ApplyStream(rule, strings.NewReader(input), &output, jsonlogic.StreamOptions{})
+	var missing *jsonlogic.MissingVariableError
+	assert.ErrorAs(t, err, &missing)
+	assert.Equal(t, "1\n", output.String())
+
+	output.Reset()
+	err = jsonlogic.ApplyStream(rule, strings.NewReader(input), &output, jsonlogic.StreamOptions{Options: &jsonlogic.Options{}})
+	assert.NoError(t, err)
+	assert.Equal(t, "1\nnull\n", output.String())
+}
+
+// gatedReader returns its first record, then blocks the next read until the
+// gate is closed.
+type gatedReader struct {
+	first []byte
+	gate  chan struct{}
+}
+
+func (r *gatedReader) Read(p []byte) (int, error) {
+	if len(r.first) > 0 {
+		n := copy(p, r.first)
+		r.first = r.first[n:]
+		return n, nil
+	}
+
+	<-r.gate
+	return 0, io.EOF
+}
+
+func TestApplyStreamWaitsForTheReadInProgress(t *testing.T) {
+	rule, err := jsonlogic.Compile([]byte(`{"reduce": [[1], {"var": "current"}, {"var": "initial"}]}`))
+	assert.NoError(t, err)
+
+	reader := &gatedReader{first: []byte("{\"initial\": null}\n"), gate: make(chan struct{})}
+
+	returned := make(chan error)
+	go func() {
+		returned <- jsonlogic.ApplyStream(rule, reader, io.Discard, jsonlogic.StreamOptions{Workers: 2})
+	}()
+
+	select {
+	case <-returned:
+		t.Fatal("the stream returned while its input was being read")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(reader.gate)
+	assert.ErrorContains(t, <-returned, "record 0")
+}
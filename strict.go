@@ -0,0 +1,210 @@
+package jsonlogic
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// TypeError represents an operation applied, in strict mode, to values of
+// types it doesn't accept. It contains the operator and the JSON types of its
+// operands.
+type TypeError struct {
+	Operator string
+	Types    []string
+}
+
+func (e *TypeError) Error() string {
+	return fmt.Sprintf("The operator \"%s\" can't be applied to %s", e.Operator, strings.Join(e.Types, ", "))
+}
+
+// MissingVariableError represents, in strict mode, a variable read without a
+// default value that doesn't exist in the data.
+type MissingVariableError struct {
+	Path string
+}
+
+func (e *MissingVariableError) Error() string {
+	return fmt.Sprintf("The variable \"%s\" is missing", e.Path)
+}
+
+// numericOperands evaluates the operands of an arithmetic operation, which
+// must all be numbers in strict mode.
+func (e *evaluator) numericOperands(values, data any) any {
+	node := e.node
+	parsed := e.parseValues(values, data)
+
+	if e.strict {
+		e.checkOperands(node, parsed, func(operands []any) bool {
+			for _, operand := range operands {
+				if _, ok := operand.(float64); !ok {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	return parsed
+}
+
+// comparableOperands evaluates the operands of an ordering comparison, which
+// must all be numbers or all be strings in strict mode.
+func (e *evaluator) comparableOperands(values, data any) any {
+	node := e.node
+	parsed := e.parseValues(values, data)
+
+	if e.strict {
+		e.checkOperands(node, parsed, func(operands []any) bool {
+			for _, operand := range operands {
+				if !isNumberOrString(operand) || !sameType(operand, operands[0]) {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	return parsed
+}
+
+// equalityOperands evaluates the operands of an equality comparison, which
+// must be primitives or null of the same type in strict mode.
+func (e *evaluator) equalityOperands(values, data any) any {
+	node := e.node
+	parsed := e.parseValues(values, data)
+
+	if e.strict {
+		e.checkOperands(node, parsed, func(operands []any) bool {
+			if len(operands) < 2 {
+				return true
+			}
+
+			a, b := operands[0], operands[1]
+			if a == nil || b == nil {
+				return a == b
+			}
+
+			return isPrimitive(a) && sameType(a, b)
+		})
+	}
+
+	return parsed
+}
+
+// checkOperands raises a TypeError when the operands aren't accepted.
+func (e *evaluator) checkOperands(node map[string]any, parsed any, accepts func(operands []any) bool) {
+	operands, ok := parsed.([]any)
+	if !ok {
+		operands = []any{parsed}
+	}
+
+	if accepts(operands) {
+		return
+	}
+
+	var operator string
+	for key := range node {
+		operator = key
+	}
+
+	types := make([]string, 0, len(operands))
+	for _, operand := range operands {
+		types = append(types, jsonType(operand))
+	}
+
+	panic(&TypeError{Operator: operator, Types: types})
+}
+
+// checkVarExists raises a MissingVariableError when the variable, described
+// by the already evaluated arguments of "var", doesn't exist in the data nor
+// in the data around the iterations reading it first.
+func (e *evaluator) checkVarExists(values, data any) {
+	path := values
+	if v, ok := values.([]any); ok {
+		if len(v) != 1 {
+			// the data itself or a variable with a default value
+			return
+		}
+		path = v[0]
+	}
+
+	if s, ok := path.(string); path == nil || (ok && s == "") {
+		return
+	}
+
+	segments, ok := parsePath(path)
+	if !ok || hasPath(segments, data) {
+		return
+	}
+
+	for i := 1; i < len(e.scopes); i++ {
+		if e.scopes[i].outerFirst && hasPath(segments, e.scopes[i-1].data) {
+			return
+		}
+	}
+
+	p, _ := ParsePath(path)
+	panic(&MissingVariableError{Path: p.String()})
+}
+
+// hasPath reports whether the path exists in the data, even if it leads to a
+// null value. Paths with wildcards always exist.
+func hasPath(segments []pathSegment, data any) bool {
+	value := data
+
+	for _, segment := range segments {
+		if segment.isWildcard() || segment.isRecursive() {
+			return true
+		}
+
+		switch v := value.(type) {
+		case map[string]any:
+			if segment.isIndex {
+				return false
+			}
+
+			var ok bool
+			if value, ok = v[segment.key]; !ok {
+				return false
+			}
+		case []any:
+			pos, err := strconv.Atoi(segment.key)
+			if err != nil || pos < 0 || pos >= len(v) {
+				return false
+			}
+			value = v[pos]
+		default:
+			return false
+		}
+	}
+
+	return true
+}
+
+func isNumberOrString(value any) bool {
+	switch value.(type) {
+	case float64, string:
+		return true
+	}
+	return false
+}
+
+// jsonType returns the JSON type of the value.
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,104 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestStrictMode(t *testing.T) {
+	data := json.RawMessage(`{"age": 30, "code": "10", "name": "ana", "active": true, "nothing": null, "tags": ["a", null], "user": {"roles": []}}`)
+	strict := jsonlogic.Options{Strict: true}
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"numbers":                       {rule: `{"+": [{"var": "age"}, 1]}`, expected: `31`},
+		"comparing numbers":             {rule: `{"<": [18, {"var": "age"}, 65]}`, expected: `true`},
+		"comparing strings":             {rule: `{"<": ["a", {"var": "name"}]}`, expected: `true`},
+		"equal strings":                 {rule: `{"==": [{"var": "code"}, "10"]}`, expected: `true`},
+		"equal booleans":                {rule: `{"!=": [{"var": "active"}, false]}`, expected: `true`},
+		"equal nulls":                   {rule: `{"==": [{"var": "nothing"}, null]}`, expected: `true`},
+		"null values exist":             {rule: `{"var": "nothing"}`, expected: `null`},
+		"null elements exist":           {rule: `{"var": "tags.1"}`, expected: `null`},
+		"defaults for absent variables": {rule: `{"var": ["missing", 0]}`, expected: `0`},
+		"val of a null value":           {rule: `{"val": ["nothing"]}`, expected: `null`},
+		"missing still works":           {rule: `{"missing": ["a", "age"]}`, expected: `["a"]`},
+		"variables of the outer data":   {rule: `{"all": [{"var": "tags"}, {"!!": [{"var": "age"}]}]}`, expected: `true`},
+		"strict equality":               {rule: `{"===": [{"var": "code"}, 10]}`, expected: `false`},
+		"truthiness":                    {rule: `{"if": [{"var": "user.roles"}, "has roles", "no roles"]}`, expected: `"no roles"`},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(fmt.Sprintf("SCENARIO:%s", name), func(t *testing.T) {
+			output, err := jsonlogic.ApplyRawWithOptions(json.RawMessage(scenario.rule), data, strict)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, string(output))
+		})
+	}
+
+	failures := map[string]struct {
+		rule string
+		err  string
+	}{
+		"arithmetic on strings":        {rule: `{"+": [{"var": "code"}, 1]}`, err: `The operator "+" can't be applied to string, number`},
+		"arithmetic on a single value": {rule: `{"-": "3"}`, err: `The operator "-" can't be applied to string`},
+		"modulo of strings":            {rule: `{"%": [{"var": "age"}, "7"]}`, err: `The operator "%" can't be applied to number, string`},
+		"comparing across types":       {rule: `{">": [{"var": "code"}, 5]}`, err: `The operator ">" can't be applied to string, number`},
+		"between across types":         {rule: `{"<=": [1, {"var": "age"}, "40"]}`, err: `The operator "<=" can't be applied to number, number, string`},
+		"equality across types":        {rule: `{"==": [{"var": "code"}, 10]}`, err: `The operator "==" can't be applied to string, number`},
+		"inequality across types":      {rule: `{"!=": [{"var": "active"}, 1]}`, err: `The operator "!=" can't be applied to boolean, number`},
+		"equality of arrays":           {rule: `{"==": [{"var": "tags"}, {"var": "tags"}]}`, err: `The operator "==" can't be applied to array, array`},
+		"absent variable":              {rule: `{"var": "user.name"}`, err: `The variable "user.name" is missing`},
+		"absent element":               {rule: `{"var": "tags.5"}`, err: `The variable "tags.5" is missing`},
+		"absent variable in map":       {rule: `{"map": [{"var": "tags"}, {"var": "age"}]}`, err: `The variable "age" is missing`},
+		"absent val":                   {rule: `{"val": ["user", "e.mail"]}`, err: `The variable "/user/e.mail" is missing`},
+	}
+
+	for name, scenario := range failures {
+		t.Run(fmt.Sprintf("FAILURE:%s", name), func(t *testing.T) {
+			_, err := jsonlogic.ApplyRawWithOptions(json.RawMessage(scenario.rule), data, strict)
+			assert.EqualError(t, err, scenario.err)
+
+			// the default mode keeps coercing
+			_, err = jsonlogic.ApplyRawWithOptions(json.RawMessage(scenario.rule), data, jsonlogic.Options{})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestStrictModeTypedErrors(t *testing.T) {
+	var data any
+	assert.NoError(t, json.Unmarshal([]byte(`{"code": "10"}`), &data))
+
+	var rule any
+	assert.NoError(t, json.Unmarshal([]byte(`{"==": [{"var": "code"}, 10]}`), &rule))
+
+	_, err := jsonlogic.ApplyInterfaceWithOptions(rule, data, jsonlogic.Options{Strict: true})
+
+	var typeErr *jsonlogic.TypeError
+	if assert.ErrorAs(t, err, &typeErr) {
+		assert.Equal(t, "==", typeErr.Operator)
+		assert.Equal(t, []string{"string", "number"}, typeErr.Types)
+	}
+
+	output, err := jsonlogic.ApplyInterfaceWithOptions(rule, data, jsonlogic.Options{})
+	assert.NoError(t, err)
+	assert.Equal(t, true, output)
+
+	assert.NoError(t, json.Unmarshal([]byte(`{"var": "user.id"}`), &rule))
+	_, err = jsonlogic.ApplyInterfaceWithOptions(rule, data, jsonlogic.Options{Strict: true})
+
+	var missingErr *jsonlogic.MissingVariableError
+	if assert.ErrorAs(t, err, &missingErr) {
+		assert.Equal(t, "user.id", missingErr.Path)
+	}
+
+	_, err = jsonlogic.ApplyInterfaceWithOptions(rule, map[string]int{"a": 1}, jsonlogic.Options{Strict: true})
+	assert.Error(t, err)
+}
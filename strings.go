@@ -2,8 +2,8 @@ package jsonlogic
 
 import "strings"
 
-func substr(values, data any) any {
-	values = parseValues(values, data)
+func (e *evaluator) substr(values, data any) any {
+	values = e.parseValues(values, data)
 	parsed := values.([]any)
 
 	runes := []rune(toString(parsed[0]))
@@ -39,18 +39,25 @@ func substr(values, data any) any {
 	return string(runes[from:to])
 }
 
-func concat(values, data any) any {
-	values = parseValues(values, data)
+func (e *evaluator) concat(values, data any) any {
+	values = e.parseValues(values, data)
 	if _, ok := values.(string); ok {
 		return values
 	}
 
-	inputSlice := values.([]any)
+	inputSlice, ok := values.([]any)
+	if !ok && e.profile != ProfileLegacy {
+		inputSlice = []any{values}
+	}
 
 	if len(inputSlice) == 0 {
 		return ""
 	}
 
+	if e.profile != ProfileLegacy {
+		return jsJoin(inputSlice, "")
+	}
+
 	if len(inputSlice) == 1 {
 		return toString(inputSlice[0])
 	}
@@ -54,6 +54,14 @@ func ValidateJsonLogic(rules any) bool {
 				return false
 			}
 
+			if operator == "let" {
+				return validateLet(value)
+			}
+
+			if signature, ok := LookupSignature(operator); ok && !signature.acceptsArgs(value) {
+				return false
+			}
+
 			return ValidateJsonLogic(value)
 		}
 	}
@@ -81,6 +89,23 @@ func ValidateJsonLogic(rules any) bool {
 	return isPrimitive(rules)
 }
 
+// validateLet checks the bindings and the body of a "let" operation, as the
+// names being bound are not operators.
+func validateLet(values any) bool {
+	bindings, body, err := letArguments(values)
+	if err != nil {
+		return false
+	}
+
+	for _, expression := range bindings {
+		if !ValidateJsonLogic(expression) {
+			return false
+		}
+	}
+
+	return ValidateJsonLogic(body)
+}
+
 func isOperator(op string) bool {
 	operatorsLock.RLock()
 	_, isOperator := operators[op]
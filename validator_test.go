@@ -40,6 +40,26 @@ func TestJSONLogicValidator(t *testing.T) {
 			IsValid: true,
 			Rule:    strings.NewReader(`{"if": [{">=": [{ "var": "amount" }, 10] }, { "var": "amount" }, { "output": true, "result": "too low" } ]}`),
 		},
+		"let binds names that aren't operators": {
+			IsValid: true,
+			Rule:    strings.NewReader(`{"let": [{"total": {"+": [{"var": "a"}, 1]}}, {">": [{"var": "total"}, 2]}]}`),
+		},
+		"let must have valid expressions": {
+			IsValid: false,
+			Rule:    strings.NewReader(`{"let": [{"total": {"plus": [1, 2]}}, {"var": "total"}]}`),
+		},
+		"let must have a valid body": {
+			IsValid: false,
+			Rule:    strings.NewReader(`{"let": [{"total": 1}, {"plus": [{"var": "total"}, 2]}]}`),
+		},
+		"let must bind an object": {
+			IsValid: false,
+			Rule:    strings.NewReader(`{"let": [["total", 1], {"var": "total"}]}`),
+		},
+		"let can't bind names with dots": {
+			IsValid: false,
+			Rule:    strings.NewReader(`{"let": [{"a.b": 1}, {"var": "a.b"}]}`),
+		},
 		"set must be valid": {
 			IsValid: true,
 			Rule: strings.NewReader(`{
@@ -0,0 +1,160 @@
+package jsonlogic
+
+// iterators are the operators that evaluate their second argument once per
+// element of the array given as the first argument.
+var iterators = map[string]bool{
+	"map":    true,
+	"filter": true,
+	"reduce": true,
+	"all":    true,
+	"none":   true,
+	"some":   true,
+}
+
+// outerFirst are the iterators whose bodies look "var" up in the enclosing
+// data before the element.
+var outerFirst = map[string]bool{
+	"filter": true,
+	"all":    true,
+	"none":   true,
+	"some":   true,
+}
+
+// Variables returns the paths of the variables the rule reads from its data,
+// in order of first appearance and without duplicates. It looks at "var",
+// "val", "missing" and "missing_some" and skips paths computed by other
+// operations.
+//
+// The bodies of map, filter, reduce, all, none and some are evaluated against
+// the elements of an array rather than the data, so only the "val" operations
+// that select the scope of the data from inside them are reported, and the
+// "var" operations that read the data before the element: when the outermost
+// iteration is filter, all, none or some, {"var": "user.min"} in its body
+// reads the data when it holds the path, while the empty paths and the ones
+// starting with "." always read the element. Variables reading the names
+// bound by "let" aren't reported either.
+func Variables(rule any) []Path {
+	seen := make(map[string]bool)
+	paths := make([]Path, 0)
+
+	add := func(path any, bound map[string]bool) {
+		if name, ok := boundName(path); ok && bound[name] {
+			return
+		}
+
+		p, ok := ParsePath(path)
+		if !ok || len(p) == 0 {
+			return
+		}
+
+		key := p.Pointer()
+		if !seen[key] {
+			seen[key] = true
+			paths = append(paths, p)
+		}
+	}
+
+	// outer tells whether "var" reads the data before the element.
+	var walk func(rule any, depth int, outer bool, bound map[string]bool)
+	walk = func(rule any, depth int, outer bool, bound map[string]bool) {
+		switch value := rule.(type) {
+		case []any:
+			for _, item := range value {
+				walk(item, depth, outer, bound)
+			}
+		case map[string]any:
+			if len(value) != 1 {
+				return
+			}
+
+			for operator, values := range value {
+				args, isSlice := values.([]any)
+
+				switch {
+				case operator == "var" && (depth == 0 || outer):
+					path := values
+					if isSlice {
+						if len(args) == 0 {
+							break
+						}
+						path = args[0]
+					}
+					if name, ok := path.(string); depth == 0 || !ok || (name != "" && name[0] != '.') {
+						add(path, bound)
+					}
+					walk(values, depth, outer, bound)
+				case operator == "val":
+					segments := args
+					if !isSlice {
+						segments = []any{values}
+					}
+					level, names := 0, bound
+					if len(segments) > 0 {
+						if l, ok := segments[0].([]any); ok && len(l) == 1 {
+							if n, ok := l[0].(float64); ok {
+								// the bound names are only read without a scope
+								level, names = int(n), nil
+								segments = segments[1:]
+							}
+						}
+					}
+					if level == depth {
+						add(segments, names)
+					}
+					walk(values, depth, outer, bound)
+				case operator == "missing" && depth == 0:
+					if !isSlice {
+						add(values, nil)
+						walk(values, depth, outer, bound)
+						break
+					}
+					for _, arg := range args {
+						add(arg, nil)
+						walk(arg, depth, outer, bound)
+					}
+				case operator == "missing_some" && depth == 0:
+					if isSlice && len(args) > 1 {
+						walk(args[0], depth, outer, bound)
+						list, ok := args[1].([]any)
+						if !ok {
+							walk(args[1], depth, outer, bound)
+							break
+						}
+						for _, arg := range list {
+							add(arg, nil)
+						}
+					}
+				case operator == "let":
+					bindings, body, err := letArguments(values)
+					if err != nil {
+						walk(values, depth, outer, bound)
+						break
+					}
+					inner := make(map[string]bool, len(bound)+len(bindings))
+					for name := range bound {
+						inner[name] = true
+					}
+					for name, expression := range bindings {
+						walk(expression, depth, outer, bound)
+						inner[name] = true
+					}
+					walk(body, depth, outer, inner)
+				case iterators[operator] && isSlice:
+					for i, arg := range args {
+						if i == 1 {
+							walk(arg, depth+1, outer || (depth == 0 && outerFirst[operator]), bound)
+						} else {
+							walk(arg, depth, outer, bound)
+						}
+					}
+				default:
+					walk(values, depth, outer, bound)
+				}
+			}
+		}
+	}
+
+	walk(rule, 0, false, nil)
+
+	return paths
+}
@@ -0,0 +1,112 @@
+package jsonlogic_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	jsonlogic "github.com/diegoholiveira/jsonlogic/v3"
+)
+
+func TestVariables(t *testing.T) {
+	var rule any
+	err := json.Unmarshal([]byte(`{"and": [
+		{"==": [{"var": "user.country"}, "BR"]},
+		{">": [{"var": ["user.age", 0]}, 18]},
+		{"missing": ["user.name", "/user/e.mail"]},
+		{"missing_some": [1, [["phones", 0], "user.country"]]},
+		{"some": [{"var": "orders"}, {"==": [{"var": "status"}, "paid"]}]},
+		{"var": {"cat": ["settings.", {"var": "locale"}]}},
+		{"var": ""},
+		{"in": ["vip", {"var": "orders.*.tags.*"}]}
+	]}`), &rule)
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, path := range jsonlogic.Variables(rule) {
+		paths = append(paths, path.String())
+	}
+
+	assert.Equal(t, []string{
+		"user.country",
+		"user.age",
+		"user.name",
+		"/user/e.mail",
+		"phones.0",
+		"orders",
+		"status",
+		"locale",
+		"orders.*.tags.*",
+	}, paths)
+
+	paths = paths[:0]
+	for _, path := range jsonlogic.Variables(rule) {
+		if path.HasWildcard() {
+			paths = append(paths, path.String())
+		}
+	}
+	assert.Equal(t, []string{"orders.*.tags.*"}, paths)
+}
+
+func TestVariablesReadBeforeTheElement(t *testing.T) {
+	scenarios := map[string]struct {
+		rule     string
+		expected []string
+	}{
+		"all": {
+			rule:     `{"all": [{"var": "items"}, {">=": [{"var": ""}, {"var": "user.min"}]}]}`,
+			expected: []string{"items", "user.min"},
+		},
+		"element": {
+			rule:     `{"filter": [{"var": "items"}, {"==": [{"var": ".status"}, "paid"]}]}`,
+			expected: []string{"items"},
+		},
+		"map": {
+			rule:     `{"map": [{"var": "items"}, {"var": "price"}]}`,
+			expected: []string{"items"},
+		},
+		"inside a map": {
+			rule:     `{"some": [{"var": "orders"}, {"map": [{"var": "items"}, {"var": "currency"}]}]}`,
+			expected: []string{"orders", "items", "currency"},
+		},
+		"inside a map body": {
+			rule:     `{"map": [{"var": "orders"}, {"some": [{"var": "items"}, {"var": "paid"}]}]}`,
+			expected: []string{"orders"},
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			var rule any
+			err := json.Unmarshal([]byte(scenario.rule), &rule)
+			assert.NoError(t, err)
+
+			var paths []string
+			for _, path := range jsonlogic.Variables(rule) {
+				paths = append(paths, path.String())
+			}
+			assert.Equal(t, scenario.expected, paths)
+		})
+	}
+}
+
+func TestVariablesWithVal(t *testing.T) {
+	var rule any
+	err := json.Unmarshal([]byte(`{"filter": [
+		{"val": "orders"},
+		{"and": [
+			{">": [{"val": "total"}, {"val": [[1], "limit"]}]},
+			{"==": [{"val": [[-1], "index"]}, 0]},
+			{"map": [{"val": "items"}, {"val": [[2], "currency"]}]}
+		]}
+	]}`), &rule)
+	assert.NoError(t, err)
+
+	var paths []string
+	for _, path := range jsonlogic.Variables(rule) {
+		paths = append(paths, path.String())
+	}
+
+	assert.Equal(t, []string{"orders", "limit", "currency"}, paths)
+}
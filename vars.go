@@ -6,7 +6,9 @@ import (
 	"strings"
 )
 
-func solveVars(values, data any) any {
+// solveVars replaces the variables found in the rule by their values in the
+// data, except for the ones reading the names in bound.
+func (e *evaluator) solveVars(values, data any, bound map[string]bool) any {
 	if m, ok := values.(map[string]any); ok {
 		if len(m) == 0 {
 			return m
@@ -20,14 +22,21 @@ func solveVars(values, data any) any {
 					continue
 				}
 
-				val := getVar(value, data)
+				if isBoundVar(value, bound) {
+					logic["var"] = value
+					continue
+				}
+
+				val := e.getVar(value, data)
 				if val != nil {
 					return val
 				}
 
 				logic["var"] = value
+			} else if key == "let" && len(m) == 1 {
+				logic["let"] = e.solveLetVars(value, data, bound)
 			} else {
-				logic[key] = solveVars(value, data)
+				logic[key] = e.solveVars(value, data, bound)
 			}
 		}
 
@@ -41,7 +50,7 @@ func solveVars(values, data any) any {
 		logic := make([]any, 0, len(s))
 
 		for _, value := range s {
-			logic = append(logic, solveVars(value, data))
+			logic = append(logic, e.solveVars(value, data, bound))
 		}
 
 		return logic
@@ -50,8 +59,87 @@ func solveVars(values, data any) any {
 	return values
 }
 
-func getVar(values, data any) any {
-	values = parseValues(values, data)
+// solveLetVars solves the variables of the arguments of "let", keeping the
+// ones that read the names it binds in its body.
+func (e *evaluator) solveLetVars(values, data any, bound map[string]bool) any {
+	bindings, body, err := letArguments(values)
+	if err != nil {
+		return e.solveVars(values, data, bound)
+	}
+
+	solved := make(map[string]any, len(bindings))
+	inner := make(map[string]bool, len(bound)+len(bindings))
+	for name := range bound {
+		inner[name] = true
+	}
+
+	for name, expression := range bindings {
+		solved[name] = e.solveVars(expression, data, bound)
+		inner[name] = true
+	}
+
+	return []any{solved, e.solveVars(body, data, inner)}
+}
+
+// isBoundVar reports whether the arguments of "var" read one of the names in bound.
+func isBoundVar(values any, bound map[string]bool) bool {
+	if len(bound) == 0 {
+		return false
+	}
+
+	if v, ok := values.([]any); ok {
+		if len(v) == 0 {
+			return false
+		}
+		values = v[0]
+	}
+
+	name, ok := boundName(values)
+	return ok && bound[name]
+}
+
+func (e *evaluator) getVar(values, data any) any {
+	values = e.parseValues(values, data)
+
+	if value, ok := e.boundVar(values); ok {
+		return value
+	}
+
+	if s, ok := values.(string); !ok || (s != "" && !strings.HasPrefix(s, ".")) {
+		if value := e.getOuterVar(values); value != nil {
+			return value
+		}
+	}
+
+	value := resolveVar(values, data)
+	if value == nil && e.strict {
+		e.checkVarExists(values, data)
+	}
+
+	return value
+}
+
+// getOuterVar looks the variable up in the data enclosing the iterations that
+// resolve variables against it before the current element, starting from the
+// outermost one. Variables that are empty or start with "." always refer to
+// the element.
+func (e *evaluator) getOuterVar(values any) any {
+	for i := 1; i < len(e.scopes); i++ {
+		if !e.scopes[i].outerFirst {
+			continue
+		}
+
+		if value := resolveVar(values, e.scopes[i-1].data); value != nil {
+			return value
+		}
+	}
+
+	return nil
+}
+
+// resolveVar returns the value of the variable, described by the already
+// evaluated arguments of "var", in the data.
+func resolveVar(values, data any) any {
 	if values == nil {
 		if !isPrimitive(data) {
 			return nil
@@ -78,52 +166,116 @@ func getVar(values, data any) any {
 			_default = v[1]
 		}
 
-		values = v[0].(string)
+		values = v[0]
 	}
 
-	if data == nil {
+	segments, ok := parsePath(values)
+	if !ok {
 		return _default
 	}
 
-	parts := strings.Split(values.(string), ".")
+	value, found := lookupPath(segments, data)
+	if !found {
+		return _default
+	}
 
-	var _value any = data
+	return value
+}
 
-	for _, part := range parts {
-		if part == "" {
-			continue
-		}
+// val reads a value from the current scope or from an enclosing one. Its
+// arguments are the segments of the path, where strings are keys used as-is
+// and numbers are array indexes:
+//
+//	{"val": "name"}                  // key "name" of the current data
+//	{"val": ["user", "e.mail"]}      // key "e.mail" inside "user"
+//	{"val": []}                      // the current data itself
+//
+// When the first argument is an array holding a number, it selects the scope:
+// [0] is the current one, [1] the data around the innermost iteration and so
+// on. Negative numbers select the iteration itself: [-1] is an object with
+// the "index" of the current element and the "array" being iterated, [-2]
+// the same for the enclosing iteration.
+//
+//	{"val": [[1], "limit"]}          // key "limit" of the data around the iteration
+//	{"val": [[-1], "index"]}         // index of the current element
+//
+// Without a scope, the names bound by "let" are read before the data.
+func (e *evaluator) val(values, data any) any {
+	values = e.parseValues(values, data)
+
+	var path []any
+
+	switch v := values.(type) {
+	case nil:
+	case []any:
+		path = v
+	default:
+		path = []any{v}
+	}
 
-		if mm, ok := _value.(map[string]any); ok {
-			_value = mm[part]
-		} else if sv, ok := _value.([]any); ok {
-			f, err := strconv.ParseFloat(part, 64)
-			if err != nil {
-				return _default
-			}
-			pos := int(f)
-			if pos < 0 || pos >= len(sv) {
-				return _default
-			}
-			_value = sv[pos]
-		} else {
-			return _default
+	target := data
+
+	if len(path) > 0 {
+		if level, ok := path[0].([]any); ok && len(level) == 1 {
+			path = path[1:]
+			target = e.scopeData(int(toNumber(level[0])), data)
+		} else if value, ok := e.boundVar([]any{path}); ok {
+			return value
 		}
+	}
 
-		if _value == nil {
-			return _default
+	segments, ok := parsePath(path)
+	if !ok {
+		return nil
+	}
+
+	value, found := lookupPath(segments, target)
+	if !found && e.strict && !hasPath(segments, target) {
+		p, _ := ParsePath(path)
+		panic(&MissingVariableError{Path: p.String()})
+	}
+
+	return value
+}
+
+// scopeData returns the data of the scope selected by val.
+func (e *evaluator) scopeData(level int, data any) any {
+	if level == 0 {
+		return data
+	}
+
+	if level > 0 {
+		i := len(e.scopes) - 1 - level
+		if i < 0 {
+			return nil
 		}
+		return e.scopes[i].data
+	}
+
+	// the root scope is not an iteration
+	i := len(e.scopes) + level
+	if i < 1 {
+		return nil
 	}
 
-	return _value
+	return map[string]any{
+		"index": float64(e.scopes[i].index),
+		"array": e.scopes[i].array,
+	}
 }
 
 func solveVarsBackToJsonLogic(rule, data any) (json.RawMessage, error) {
 	ruleMap := rule.(map[string]any)
 	result := make(map[string]any)
 
+	e := newEvaluator()
+
 	for operator, values := range ruleMap {
-		result[operator] = solveVars(values, data)
+		if operator == "let" && len(ruleMap) == 1 {
+			result[operator] = e.solveLetVars(values, data, nil)
+			continue
+		}
+		result[operator] = e.solveVars(values, data, nil)
 	}
 
 	resultJson, err := json.Marshal(result)
@@ -167,8 +319,8 @@ func deepCopyAny(v any) any {
 	}
 }
 
-func setProperty(values, data any) any {
-	parsed, ok := parseValues(values, data).([]any)
+func (e *evaluator) setProperty(values, data any) any {
+	parsed, ok := e.parseValues(values, data).([]any)
 	if !ok {
 		return nil
 	}
@@ -191,7 +343,7 @@ func setProperty(values, data any) any {
 	}
 
 	modified := deepCopyMap(object)
-	modified[property] = parseValues(parsed[2], data)
+	modified[property] = e.parseValues(parsed[2], data)
 
 	return modified
 }
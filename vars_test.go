@@ -122,3 +122,185 @@ func TestSetPropertyWithOnlyObjectArgument(t *testing.T) {
 	assert.NoError(t, err)
 	assert.JSONEq(t, `{"a":1,"b":2}`, string(output))
 }
+
+func TestGetVarWithPathSyntaxes(t *testing.T) {
+	data := json.RawMessage(`{
+		"domains": {"example.com": {"owner": "ana"}},
+		"a/b": {"c~d": 1},
+		"list": [{"0": "key"}, "second"],
+		"object": {"0": "zero"}
+	}`)
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"json pointer":                     {rule: `{"var": "/domains/example.com/owner"}`, expected: `"ana"`},
+		"json pointer with escapes":        {rule: `{"var": "/a~1b/c~0d"}`, expected: `1`},
+		"json pointer with default":        {rule: `{"var": ["/domains/example.org/owner", "nobody"]}`, expected: `"nobody"`},
+		"segments":                         {rule: `{"var": [["domains", "example.com", "owner"]]}`, expected: `"ana"`},
+		"segments with default":            {rule: `{"var": [["domains", "example.org"], "nobody"]}`, expected: `"nobody"`},
+		"numeric segment indexes arrays":   {rule: `{"var": [["list", 1]]}`, expected: `"second"`},
+		"numeric segment skips object key": {rule: `{"var": [["object", 0], "none"]}`, expected: `"none"`},
+		"string segment reads object key":  {rule: `{"var": [["list", 0, "0"]]}`, expected: `"key"`},
+		"dotted paths still split":         {rule: `{"var": ["domains.example.com.owner", "unreachable"]}`, expected: `"unreachable"`},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			output, err := jsonlogic.ApplyRaw(json.RawMessage(scenario.rule), data)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, string(output))
+		})
+	}
+}
+
+func TestMissingWithPathSyntaxes(t *testing.T) {
+	data := json.RawMessage(`{"domains": {"example.com": {"owner": "ana"}}}`)
+
+	output, err := jsonlogic.ApplyRaw(json.RawMessage(`{"missing": [
+		"/domains/example.com/owner",
+		"/domains/example.org/owner",
+		["domains", "example.com"],
+		["domains", "example.org"]
+	]}`), data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `["/domains/example.org/owner", ["domains", "example.org"]]`, string(output))
+
+	output, err = jsonlogic.ApplyRaw(json.RawMessage(`{"missing_some": [1, ["/domains/example.com/owner", ["nope"]]]}`), data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(output))
+}
+
+func TestGetJsonLogicWithSolvedVarsWithPathSyntaxes(t *testing.T) {
+	rule := json.RawMessage(`{"==": [{"var": "/user/e.mail"}, {"var": [["user", "name"]]}]}`)
+	data := json.RawMessage(`{"user": {"e.mail": "ana@example.com", "name": "ana"}}`)
+
+	output, err := jsonlogic.GetJsonLogicWithSolvedVars(rule, data)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"==": ["ana@example.com", "ana"]}`, string(output))
+}
+
+func TestParsePath(t *testing.T) {
+	scenarios := map[string]struct {
+		path     any
+		expected jsonlogic.Path
+		text     string
+	}{
+		"dotted":   {path: "a.b.c", expected: jsonlogic.Path{"a", "b", "c"}, text: "a.b.c"},
+		"number":   {path: float64(1), expected: jsonlogic.Path{"1"}, text: "1"},
+		"pointer":  {path: "/a/b.c/~1d", expected: jsonlogic.Path{"a", "b.c", "/d"}, text: "/a/b.c/~1d"},
+		"segments": {path: []any{"a", "b.c", float64(0)}, expected: jsonlogic.Path{"a", "b.c", "0"}, text: "/a/b.c/0"},
+		"slash":    {path: []any{"/x", "y"}, expected: jsonlogic.Path{"/x", "y"}, text: "/~1x/y"},
+		"root":     {path: "/", expected: jsonlogic.Path{""}, text: "/"},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			path, ok := jsonlogic.ParsePath(scenario.path)
+			assert.True(t, ok)
+			assert.Equal(t, scenario.expected, path)
+			assert.Equal(t, scenario.text, path.String())
+		})
+	}
+
+	_, ok := jsonlogic.ParsePath([]any{"a", true})
+	assert.False(t, ok)
+
+	_, ok = jsonlogic.ParsePath(map[string]any{"var": "a"})
+	assert.False(t, ok)
+}
+
+func TestPathStringRoundTrip(t *testing.T) {
+	paths := []jsonlogic.Path{
+		{},
+		{"a"},
+		{"a", "b"},
+		{"/x"},
+		{"/x", "y"},
+		{"a", "/x"},
+		{""},
+		{"a", ""},
+		{"a.b", "c"},
+		{"~1", "/"},
+		{"*", "**"},
+	}
+
+	for _, path := range paths {
+		t.Run(path.String(), func(t *testing.T) {
+			parsed, ok := jsonlogic.ParsePath(path.String())
+			assert.True(t, ok)
+			assert.Equal(t, path, parsed)
+		})
+	}
+}
+
+func TestGetVarWithWildcards(t *testing.T) {
+	data := json.RawMessage(`{
+		"users": [{"name": "Ana", "age": 30}, {"name": "Bia", "age": 12}, {"name": "Caio"}],
+		"orders": [
+			{"items": [{"sku": "a1"}, {"sku": "b2"}]},
+			{"items": [{"sku": "c3"}]},
+			{"items": []}
+		],
+		"matrix": [[1, 2], [3, [4]]],
+		"tree": {"b": {"sku": "x"}, "a": {"child": {"sku": "y"}}}
+	}`)
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"every element":            {rule: `{"var": "users.*.age"}`, expected: `[30, 12]`},
+		"nested arrays":            {rule: `{"var": "orders.*.items.*.sku"}`, expected: `["a1", "b2", "c3"]`},
+		"arrays of arrays":         {rule: `{"var": "matrix.*.*"}`, expected: `[1, 2, 3, [4]]`},
+		"object values by key":     {rule: `{"var": "tree.*.sku"}`, expected: `["x"]`},
+		"recursive descent":        {rule: `{"var": "tree.**.sku"}`, expected: `["y", "x"]`},
+		"recursive from the root":  {rule: `{"var": "**.sku"}`, expected: `["a1", "b2", "c3", "y", "x"]`},
+		"json pointer":             {rule: `{"var": "/users/*/name"}`, expected: `["Ana", "Bia", "Caio"]`},
+		"no match returns null":    {rule: `{"var": "users.*.email"}`, expected: `null`},
+		"no match returns default": {rule: `{"var": ["users.*.email", []]}`, expected: `[]`},
+		"used by some": {
+			rule:     `{"some": [{"var": "orders.*.items.*.sku"}, {"==": [{"var": ""}, "c3"]}]}`,
+			expected: `true`,
+		},
+		"missing": {
+			rule:     `{"missing": ["users.*.name", "users.*.email"]}`,
+			expected: `["users.*.email"]`,
+		},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			output, err := jsonlogic.ApplyRaw(json.RawMessage(scenario.rule), data)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, string(output))
+		})
+	}
+}
+
+func TestGetVarWithLiteralWildcardKeys(t *testing.T) {
+	data := json.RawMessage(`{
+		"rates": {"*": 0.1, "BR": 0.2},
+		"paths": {"**": {"sku": "any"}, "a": {"sku": "x"}}
+	}`)
+
+	scenarios := map[string]struct {
+		rule     string
+		expected string
+	}{
+		"key named *":  {rule: `{"var": [["rates", "*"]]}`, expected: `0.1`},
+		"key named **": {rule: `{"var": [["paths", "**", "sku"]]}`, expected: `"any"`},
+		"wildcard":     {rule: `{"var": "rates.*"}`, expected: `[0.1, 0.2]`},
+		"missing":      {rule: `{"missing": [["rates", "*"], ["rates", "US"]]}`, expected: `[["rates", "US"]]`},
+		"default":      {rule: `{"var": [["rates", "**"], 0]}`, expected: `0`},
+	}
+
+	for name, scenario := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			output, err := jsonlogic.ApplyRaw(json.RawMessage(scenario.rule), data)
+			assert.NoError(t, err)
+			assert.JSONEq(t, scenario.expected, string(output))
+		})
+	}
+}